@@ -79,6 +79,13 @@ func checkIAASModelCredential(openParams environs.OpenParams, backend Persistent
 	if err != nil {
 		return params.ErrorResults{}, errors.Trace(err)
 	}
+	// Providers that can check a credential directly give a clearer
+	// error, such as missing permissions, than a failed instance listing.
+	if checker, ok := env.Provider().(environs.CredentialChecker); ok {
+		if err := checker.CheckCredential(openParams.Cloud); err != nil {
+			return params.ErrorResults{}, errors.Trace(err)
+		}
+	}
 	// We only check persisted machines vs known cloud instances.
 	// In the future, this check may be extended to other cloud resources,
 	// entities and operation-level authorisations such as interfaces,
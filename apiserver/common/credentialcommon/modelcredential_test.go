@@ -327,6 +327,39 @@ func (s *ModelCredentialSuite) TestOpeningProviderFails(c *gc.C) {
 	c.Assert(results, gc.DeepEquals, params.ErrorResults{})
 }
 
+func (s *ModelCredentialSuite) TestIAASCredentialCheckedByProvider(c *gc.C) {
+	checker := &mockCredentialChecker{Stub: &testing.Stub{}}
+	provider := &mockProvider{
+		Stub: &testing.Stub{},
+		allInstancesFunc: func(ctx context.ProviderCallContext) ([]instances.Instance, error) {
+			return []instances.Instance{}, nil
+		},
+	}
+	s.PatchValue(credentialcommon.NewEnv, func(environs.OpenParams) (environs.Environ, error) {
+		return &mockEnviron{mockProvider: provider, provider: checker}, nil
+	})
+	spec := environs.CloudSpec{Type: "nuage", Name: "nuage"}
+	results, err := credentialcommon.CheckIAASModelCredential(environs.OpenParams{Cloud: spec}, s.backend, s.callContext)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{})
+	checker.CheckCall(c, 0, "CheckCredential", spec)
+	provider.CheckCallNames(c, "AllInstances")
+}
+
+func (s *ModelCredentialSuite) TestIAASCredentialRejectedByProvider(c *gc.C) {
+	checker := &mockCredentialChecker{Stub: &testing.Stub{}}
+	checker.SetErrors(errors.Unauthorizedf("credential lacks permissions"))
+	provider := &mockProvider{Stub: &testing.Stub{}}
+	s.PatchValue(credentialcommon.NewEnv, func(environs.OpenParams) (environs.Environ, error) {
+		return &mockEnviron{mockProvider: provider, provider: checker}, nil
+	})
+	results, err := credentialcommon.CheckIAASModelCredential(environs.OpenParams{}, s.backend, s.callContext)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+	c.Assert(err, gc.ErrorMatches, "credential lacks permissions")
+	c.Assert(results, gc.DeepEquals, params.ErrorResults{})
+	provider.CheckNoCalls(c)
+}
+
 func (s *ModelCredentialSuite) TestValidateNewModelCredentialForIAASModel(c *gc.C) {
 	s.ensureEnvForIAASModel(c)
 	results, err := credentialcommon.ValidateNewModelCredential(s.backend, s.callContext, names.CloudCredentialTag{}, &testCredential)
@@ -593,12 +626,27 @@ var (
 type mockEnviron struct {
 	environs.Environ
 	*mockProvider
+	provider environs.EnvironProvider
+}
+
+func (m *mockEnviron) Provider() environs.EnvironProvider {
+	return m.provider
 }
 
 func (m *mockEnviron) AllInstances(ctx context.ProviderCallContext) ([]instances.Instance, error) {
 	return m.mockProvider.AllInstances(ctx)
 }
 
+type mockCredentialChecker struct {
+	environs.EnvironProvider
+	*testing.Stub
+}
+
+func (m *mockCredentialChecker) CheckCredential(spec environs.CloudSpec) error {
+	m.MethodCall(m, "CheckCredential", spec)
+	return m.NextErr()
+}
+
 type mockCaasBroker struct {
 	caas.Broker
 
@@ -93,7 +93,7 @@ func (s *rebootSuite) SetUpTest(c *gc.C) {
 	container, err := s.State.AddMachineInsideMachine(template, machine.Id(), instance.LXD)
 	c.Assert(err, jc.ErrorIsNil)
 
-	nestedContainer, err := s.State.AddMachineInsideMachine(template, container.Id(), instance.KVM)
+	nestedContainer, err := s.State.AddMachineInsideMachine(template, container.Id(), instance.LXD)
 	c.Assert(err, jc.ErrorIsNil)
 
 	s.machine = s.setUpMachine(c, machine)
@@ -37,6 +37,7 @@ import (
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs"
+	jujunames "github.com/juju/juju/juju/names"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
@@ -482,6 +483,9 @@ func deployApplication(
 	registry storage.ProviderRegistry,
 	storageValidator caas.StorageValidator,
 ) error {
+	if jujunames.IsReservedName(args.ApplicationName) {
+		return errors.Errorf("application name %q is reserved", args.ApplicationName)
+	}
 	curl, err := charm.ParseURL(args.CharmURL)
 	if err != nil {
 		return errors.Trace(err)
@@ -506,6 +506,27 @@ func (s *applicationSuite) TestApplicationDeploy(c *gc.C) {
 	c.Assert(files, gc.HasLen, 0)
 }
 
+func (s *applicationSuite) TestApplicationDeployReservedName(c *gc.C) {
+	curl, _ := s.UploadCharm(c, "precise/dummy-42", "dummy")
+	err := application.AddCharmWithAuthorization(application.NewStateShim(s.State), params.AddCharmWithAuthorization{
+		URL: curl.String(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	args := params.ApplicationDeploy{
+		ApplicationName: "jujud",
+		CharmURL:        curl.String(),
+		NumUnits:        1,
+	}
+	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{args}},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `application name "jujud" is reserved`)
+	_, err = s.State.Application("jujud")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *applicationSuite) TestApplicationDeployWithInvalidPlacement(c *gc.C) {
 	curl, _ := s.UploadCharm(c, "precise/dummy-42", "dummy")
 	err := application.AddCharmWithAuthorization(application.NewStateShim(s.State), params.AddCharmWithAuthorization{
@@ -1199,6 +1199,32 @@ func (s *clientSuite) TestClientAddMachineInsideMachine(c *gc.C) {
 	c.Assert(machines[0].Machine, gc.Equals, "0/lxd/0")
 }
 
+func (s *clientSuite) TestClientAddMachinesUnsupportedNesting(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, "0", instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+
+	machines, err := s.APIState.Client().AddMachines([]params.AddMachineParams{{
+		Jobs:      []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		Placement: &instance.Placement{Scope: "kvm", Directive: "0/lxd/0"},
+		Series:    "quantal",
+	}, {
+		Jobs:          []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+		ContainerType: instance.KVM,
+		ParentId:      "0/lxd/0",
+		Series:        "quantal",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 2)
+	for _, machine := range machines {
+		c.Check(machine.Error, gc.ErrorMatches, "cannot add a new machine: kvm container inside lxd container not supported")
+	}
+}
+
 func (s *clientSuite) TestClientAddMachinesWithConstraints(c *gc.C) {
 	apiParams := make([]params.AddMachineParams, 3)
 	for i := 0; i < 3; i++ {
@@ -65,6 +65,11 @@ const (
 	// a JSON file.
 	JSONFileAuthType AuthType = "jsonfile"
 
+	// ServiceAccountImpersonationAuthType is an authentication type that
+	// uses a source credential to obtain short-lived tokens for a
+	// different, impersonated service account.
+	ServiceAccountImpersonationAuthType AuthType = "service-account-impersonation"
+
 	// CertificateAuthType is an authentication type using certificates.
 	CertificateAuthType AuthType = "certificate"
 
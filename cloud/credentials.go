@@ -213,6 +213,10 @@ func (s CredentialSchema) Finalize(
 		name := field.Name
 		if field.FilePath {
 			pathValue, ok := resultMap[name]
+			if ok && field.AllowInlineJSON && IsInlineJSON(pathValue.(string)) {
+				newAttrs[name] = pathValue.(string)
+				continue
+			}
 			if ok && pathValue != "" {
 				absPath, err := ValidateFileAttrValue(pathValue.(string))
 				if err != nil {
@@ -236,6 +240,12 @@ func (s CredentialSchema) Finalize(
 	return newAttrs, nil
 }
 
+// IsInlineJSON reports whether the value of a FilePath attribute that
+// allows inline JSON holds a JSON document rather than a file path.
+func IsInlineJSON(value string) bool {
+	return strings.HasPrefix(strings.TrimSpace(value), "{")
+}
+
 // ValidateFileAttrValue returns the normalised file path, so
 // long as the specified path is valid and not a directory.
 func ValidateFileAttrValue(path string) (string, error) {
@@ -344,6 +354,11 @@ type CredentialAttr struct {
 	// of the file when the credential is "finalized".
 	FilePath bool
 
+	// AllowInlineJSON is true if the value of a FilePath attribute may
+	// be given as a JSON document instead of a file path. Values that
+	// begin with "{" are then taken to be the contents of the file.
+	AllowInlineJSON bool
+
 	// ExpandFilePath reads in the FilePath, validating the file path correctly.
 	// If the file path is correct, it will then read and replace the path,
 	// with the associated content. The contents of the file in "finalized" will
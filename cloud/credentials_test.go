@@ -609,6 +609,53 @@ func (s *credentialsSuite) TestFinalizeCredentialRelativeFilePath(c *gc.C) {
 	})
 }
 
+func (s *credentialsSuite) TestFinalizeCredentialInlineJSON(c *gc.C) {
+	contents := `{"type": "service_account"}`
+	cred := cloud.NewCredential(
+		cloud.JSONFileAuthType,
+		map[string]string{
+			"file": contents,
+		},
+	)
+	schema := cloud.CredentialSchema{{
+		"file", cloud.CredentialAttr{FilePath: true, AllowInlineJSON: true},
+	}}
+	readFile := func(path string) ([]byte, error) {
+		c.Fatalf("unexpected read of %q", path)
+		return nil, nil
+	}
+	newCred, err := cloud.FinalizeCredential(cred, map[cloud.AuthType]cloud.CredentialSchema{
+		cloud.JSONFileAuthType: schema,
+	}, readFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(newCred.Attributes(), jc.DeepEquals, map[string]string{
+		"file": contents,
+	})
+}
+
+func (s *credentialsSuite) TestFinalizeCredentialInlineJSONNotAllowed(c *gc.C) {
+	cred := cloud.NewCredential(
+		cloud.JSONFileAuthType,
+		map[string]string{
+			"file": `{"type": "service_account"}`,
+		},
+	)
+	schema := cloud.CredentialSchema{{
+		"file", cloud.CredentialAttr{FilePath: true},
+	}}
+	_, err := cloud.FinalizeCredential(cred, map[cloud.AuthType]cloud.CredentialSchema{
+		cloud.JSONFileAuthType: schema,
+	}, nil)
+	c.Assert(err, gc.ErrorMatches, "invalid file path: .*")
+}
+
+func (s *credentialsSuite) TestIsInlineJSON(c *gc.C) {
+	c.Assert(cloud.IsInlineJSON(`{"type": "service_account"}`), jc.IsTrue)
+	c.Assert(cloud.IsInlineJSON("  \n{}"), jc.IsTrue)
+	c.Assert(cloud.IsInlineJSON("/path/to/key.json"), jc.IsFalse)
+	c.Assert(cloud.IsInlineJSON(""), jc.IsFalse)
+}
+
 func (s *credentialsSuite) TestFinalizeCredentialInvalidFilePath(c *gc.C) {
 	cred := cloud.NewCredential(
 		cloud.JSONFileAuthType,
@@ -61,7 +61,7 @@ clouds:
   google:
     type: gce
     description: Google Cloud Platform
-    auth-types: [ jsonfile, oauth2 ]
+    auth-types: [ jsonfile, oauth2, service-account-impersonation ]
     regions:
       us-east1:
         endpoint: https://www.googleapis.com
@@ -4,6 +4,7 @@
 package action
 
 import (
+	jujuclock "github.com/juju/clock"
 	"github.com/juju/cmd"
 	"gopkg.in/juju/names.v2"
 
@@ -15,6 +16,7 @@ import (
 var (
 	NewActionAPIClient = &newAPIClient
 	AddValueToMap      = addValueToMap
+	StatusPollInterval = statusPollInterval
 )
 
 type ShowOutputCommand struct {
@@ -72,7 +74,11 @@ func NewShowOutputCommandForTest(store jujuclient.ClientStore) (cmd.Command, *Sh
 }
 
 func NewStatusCommandForTest(store jujuclient.ClientStore) (cmd.Command, *StatusCommand) {
-	c := &statusCommand{}
+	return NewStatusCommandWithClockForTest(store, jujuclock.WallClock)
+}
+
+func NewStatusCommandWithClockForTest(store jujuclient.ClientStore, clock jujuclock.Clock) (cmd.Command, *StatusCommand) {
+	c := &statusCommand{clock: clock}
 	c.SetClientStore(store)
 	return modelcmd.Wrap(c), &StatusCommand{c}
 }
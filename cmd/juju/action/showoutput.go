@@ -210,21 +210,31 @@ func FormatActionResult(result params.ActionResult) map[string]interface{} {
 		response["results"] = result.Output
 	}
 
-	if result.Enqueued.IsZero() && result.Started.IsZero() && result.Completed.IsZero() {
-		return response
+	if timing := formatTiming(result); len(timing) > 0 {
+		response["timing"] = timing
 	}
+	return response
+}
 
-	responseTiming := make(map[string]string)
+// formatTiming returns the times recorded for the given ActionResult,
+// as shown by both show-action-output and show-action-status. The
+// duration is only included once the Action has both started and
+// completed, as a human readable string and in nanoseconds.
+func formatTiming(result params.ActionResult) map[string]interface{} {
+	timing := make(map[string]interface{})
 	for k, v := range map[string]time.Time{
 		"enqueued":  result.Enqueued,
 		"started":   result.Started,
 		"completed": result.Completed,
 	} {
 		if !v.IsZero() {
-			responseTiming[k] = v.String()
+			timing[k] = v.String()
 		}
 	}
-	response["timing"] = responseTiming
-
-	return response
+	if !result.Started.IsZero() && !result.Completed.IsZero() {
+		duration := result.Completed.Sub(result.Started)
+		timing["duration"] = duration.String()
+		timing["duration-ns"] = duration.Nanoseconds()
+	}
+	return timing
 }
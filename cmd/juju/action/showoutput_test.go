@@ -166,6 +166,8 @@ results:
 status: complete
 timing:
   completed: 2015-02-14 08:15:30 +0000 UTC
+  duration: 30s
+  duration-ns: 30000000000
   enqueued: 2015-02-14 08:13:00 +0000 UTC
   started: 2015-02-14 08:15:00 +0000 UTC
 `[1:],
@@ -217,6 +219,8 @@ results:
 status: pending
 timing:
   completed: 2015-02-14 08:15:30 +0000 UTC
+  duration: 30s
+  duration-ns: 30000000000
   started: 2015-02-14 08:15:00 +0000 UTC
 `[1:],
 	}, {
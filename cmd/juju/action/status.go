@@ -4,8 +4,10 @@
 package action
 
 import (
+	"strings"
 	"time"
 
+	jujuclock "github.com/juju/clock"
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	"github.com/juju/gnuflag"
@@ -14,11 +16,10 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/modelcmd"
-	"github.com/juju/juju/cmd/output"
 )
 
 func NewStatusCommand() cmd.Command {
-	return modelcmd.Wrap(&statusCommand{})
+	return modelcmd.Wrap(&statusCommand{clock: jujuclock.WallClock})
 }
 
 // statusCommand shows the status of an Action by ID.
@@ -27,18 +28,56 @@ type statusCommand struct {
 	out         cmd.Output
 	requestedId string
 	name        string
+	wait        waitFlag
+	statuses    []string
+	clock       jujuclock.Clock
 }
 
 const statusDoc = `
 Show the status of Actions matching given ID, partial ID prefix, or all Actions if no ID is supplied.
 If --name <name> is provided the search will be done by name rather than by ID.
+
+To block until all the matching Actions have finished, use --wait. A timeout
+may be given, as in --wait=5s or --wait=1h; --wait=0 waits indefinitely. The
+status is shown once the Actions have finished or the timeout is reached, and
+the command fails if any of the Actions failed or were cancelled.
+
+To only show Actions with a particular status, use --status. It may be
+repeated, or given a comma separated list, to show Actions with any of the
+given statuses. Valid statuses are: cancelled, completed, failed, pending
+and running.
+
+When --format is given as yaml or json, the output also includes the times at
+which each Action was enqueued, started and completed, and how long it took to
+run, both as a duration string and in nanoseconds.
 `
 
+// validStatuses holds the Action statuses accepted by --status.
+var validStatuses = []string{
+	params.ActionCancelled,
+	params.ActionCompleted,
+	params.ActionFailed,
+	params.ActionPending,
+	params.ActionRunning,
+}
+
+// statusPollInterval is how often the Actions are queried when waiting
+// for them to finish.
+const statusPollInterval = 2 * time.Second
+
 // Set up the output.
 func (c *statusCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ActionCommandBase.SetFlags(f)
-	c.out.AddFlags(f, "yaml", output.DefaultFormatters)
+	// The "default" format preserves the original YAML output. Asking
+	// for yaml or json explicitly adds the timing of each Action.
+	c.out.AddFlags(f, "default", map[string]cmd.Formatter{
+		"default": cmd.FormatYaml,
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+	})
 	f.StringVar(&c.name, "name", "", "Action name")
+	f.Var(&c.wait, "wait", "Wait for the actions to finish, with optional timeout")
+	f.Var(cmd.NewAppendStringsValue(&c.statuses), "status", "Only show actions with these statuses")
 }
 
 func (c *statusCommand) Info() *cmd.Info {
@@ -51,6 +90,14 @@ func (c *statusCommand) Info() *cmd.Info {
 }
 
 func (c *statusCommand) Init(args []string) error {
+	for _, status := range c.statuses {
+		if !isValidStatus(status) {
+			return errors.Errorf("invalid status %q, expected one of: %s", status, strings.Join(validStatuses, ", "))
+		}
+	}
+	if c.wait.set && !c.wait.forever && c.wait.d < 0 {
+		return errors.Errorf("--wait timeout %v must not be negative", c.wait.d)
+	}
 	switch len(args) {
 	case 0:
 		c.requestedId = ""
@@ -70,24 +117,59 @@ func (c *statusCommand) Run(ctx *cmd.Context) error {
 	}
 	defer api.Close()
 
+	results, err := c.fetchResults(api)
+	if err != nil {
+		return err
+	}
+	if !c.waiting() {
+		return c.out.Write(ctx, c.formatResults(c.filterResults(results)))
+	}
+
+	results, waitErr := c.waitForResults(api, results)
+	if waitErr != nil && !errors.IsTimeout(waitErr) {
+		return errors.Trace(waitErr)
+	}
+	// Only the Actions that are shown count towards the failures.
+	filtered := c.filterResults(results)
+	if err := c.out.Write(ctx, c.formatResults(filtered)); err != nil {
+		return errors.Trace(err)
+	}
+	if waitErr != nil {
+		return waitErr
+	}
+	failed := 0
+	for _, result := range filtered {
+		if result.Error != nil || result.Status == params.ActionFailed || result.Status == params.ActionCancelled {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("%d of %d actions failed or were cancelled", failed, len(filtered))
+	}
+	return nil
+}
+
+// fetchResults returns the results of the Actions matching either the
+// requested name or ID prefix.
+func (c *statusCommand) fetchResults(api APIClient) ([]params.ActionResult, error) {
 	if c.name != "" {
 		actions, err := GetActionsByName(api, c.name)
 		if err != nil {
-			return errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
-		return c.out.Write(ctx, resultsToMap(actions))
+		return actions, nil
 	}
 
 	actionTags, err := getActionTagsByPrefix(api, c.requestedId)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(actionTags) < 1 {
 		if len(c.requestedId) == 0 {
-			return errors.Errorf("no actions found")
+			return nil, errors.Errorf("no actions found")
 		} else {
-			return errors.Errorf("no actions found matching prefix %q", c.requestedId)
+			return nil, errors.Errorf("no actions found matching prefix %q", c.requestedId)
 		}
 	}
 
@@ -98,14 +180,89 @@ func (c *statusCommand) Run(ctx *cmd.Context) error {
 
 	actions, err := api.Actions(params.Entities{Entities: entities})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(actions.Results) < 1 {
-		return errors.Errorf("identifier %q matched action(s) %v, but found no results", c.requestedId, actionTags)
+		return nil, errors.Errorf("identifier %q matched action(s) %v, but found no results", c.requestedId, actionTags)
 	}
+	return actions.Results, nil
+}
 
-	return c.out.Write(ctx, resultsToMap(actions.Results))
+// formatResults returns the results ready to be served to the formatter,
+// including their timing unless the default format was selected.
+func (c *statusCommand) formatResults(results []params.ActionResult) map[string]interface{} {
+	if c.out.Name() == "default" {
+		return resultsToMap(results)
+	}
+	return resultsToTimedMap(results)
+}
+
+// filterResults returns the results whose status was requested with
+// --status, or all of the results if no status was requested.
+func (c *statusCommand) filterResults(results []params.ActionResult) []params.ActionResult {
+	if len(c.statuses) == 0 {
+		return results
+	}
+	filtered := []params.ActionResult{}
+	for _, result := range results {
+		for _, status := range c.statuses {
+			if result.Status == status {
+				filtered = append(filtered, result)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// isValidStatus reports whether status is a known Action status.
+func isValidStatus(status string) bool {
+	for _, valid := range validStatuses {
+		if status == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// waiting reports whether --wait was given.
+func (c *statusCommand) waiting() bool {
+	return c.wait.forever || c.wait.set
+}
+
+// waitForResults queries the API until none of the results are pending
+// or running. If the --wait timeout is reached first, the latest results
+// are returned along with a timeout error.
+func (c *statusCommand) waitForResults(api APIClient, results []params.ActionResult) ([]params.ActionResult, error) {
+	var timeout <-chan time.Time
+	if !c.wait.forever && c.wait.d > 0 {
+		timeout = c.clock.After(c.wait.d)
+	}
+
+	for !resultsFinished(results) {
+		select {
+		case <-timeout:
+			return results, errors.NewTimeout(nil, "timeout reached")
+		case <-c.clock.After(statusPollInterval):
+		}
+		var err error
+		if results, err = c.fetchResults(api); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+// resultsFinished reports whether none of the results are pending or running.
+func resultsFinished(results []params.ActionResult) bool {
+	for _, result := range results {
+		switch result.Status {
+		case params.ActionPending, params.ActionRunning:
+			return false
+		}
+	}
+	return true
 }
 
 // resultsToMap is a helper function that takes in a []params.ActionResult
@@ -119,6 +276,20 @@ func resultsToMap(results []params.ActionResult) map[string]interface{} {
 	return map[string]interface{}{"actions": items}
 }
 
+// resultsToTimedMap is like resultsToMap, but also includes when each
+// Action was enqueued, started and completed, and how long it ran for.
+func resultsToTimedMap(results []params.ActionResult) map[string]interface{} {
+	items := []map[string]interface{}{}
+	for _, result := range results {
+		item := resultToMap(result)
+		if timing := formatTiming(result); len(timing) > 0 {
+			item["timing"] = timing
+		}
+		items = append(items, item)
+	}
+	return map[string]interface{}{"actions": items}
+}
+
 func resultToMap(result params.ActionResult) map[string]interface{} {
 	item := map[string]interface{}{}
 	if result.Error != nil {
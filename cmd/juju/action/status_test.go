@@ -5,15 +5,19 @@ package action_test
 
 import (
 	"bytes"
+	"encoding/json"
 	"time"
 
+	"github.com/juju/clock/testclock"
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
+	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/action"
+	coretesting "github.com/juju/juju/testing"
 )
 
 type StatusSuite struct {
@@ -92,6 +96,317 @@ func (s *StatusSuite) TestRun(c *gc.C) {
 	}
 }
 
+// pendingAPIClient reports the Actions as pending for the given number
+// of calls to Actions, before returning the canned results.
+type pendingAPIClient struct {
+	*fakeAPIClient
+	pending int
+}
+
+func (c *pendingAPIClient) Actions(args params.Entities) (params.ActionResults, error) {
+	if c.pending > 0 {
+		c.pending--
+		return params.ActionResults{Results: []params.ActionResult{{
+			Status: params.ActionPending,
+		}}}, nil
+	}
+	return c.fakeAPIClient.Actions(args)
+}
+
+func (s *StatusSuite) TestRunWait(c *gc.C) {
+	prefix := "deadbeef"
+	faketag := "action-" + prefix + "-0000-4000-8000-feedfacebeef"
+	completed := []params.ActionResult{{Status: params.ActionCompleted, Action: &params.Action{Tag: faketag, Name: "fakeName"}}}
+	failed := []params.ActionResult{{Status: params.ActionFailed, Action: &params.Action{Tag: faketag, Name: "fakeName"}}}
+
+	for i, test := range []struct {
+		about   string
+		wait    string
+		pending int
+		// The clock is moved forward by advance the given number of
+		// times, each once the command is waiting on waiters timers.
+		advance      time.Duration
+		advances     int
+		waiters      int
+		results      []params.ActionResult
+		expectStatus string
+		expectError  string
+	}{{
+		about:        "wait with a timeout for an action to complete",
+		wait:         "--wait=5s",
+		pending:      2,
+		advance:      action.StatusPollInterval,
+		advances:     2,
+		waiters:      2,
+		results:      completed,
+		expectStatus: params.ActionCompleted,
+	}, {
+		about:        "a zero timeout waits indefinitely",
+		wait:         "--wait=0",
+		pending:      3,
+		advance:      action.StatusPollInterval,
+		advances:     3,
+		waiters:      1,
+		results:      completed,
+		expectStatus: params.ActionCompleted,
+	}, {
+		about:        "wait without a timeout",
+		wait:         "--wait",
+		pending:      2,
+		advance:      action.StatusPollInterval,
+		advances:     2,
+		waiters:      1,
+		results:      completed,
+		expectStatus: params.ActionCompleted,
+	}, {
+		about:        "timeout reached while the action is pending",
+		wait:         "--wait=1s",
+		pending:      2,
+		advance:      time.Second,
+		advances:     1,
+		waiters:      2,
+		results:      completed,
+		expectStatus: params.ActionPending,
+		expectError:  "timeout reached",
+	}, {
+		about:        "a failed action is an error",
+		wait:         "--wait",
+		results:      failed,
+		expectStatus: params.ActionFailed,
+		expectError:  "1 of 1 actions failed or were cancelled",
+	}, {
+		about:       "a negative timeout is rejected",
+		wait:        "--wait=-5s",
+		results:     completed,
+		expectError: "--wait timeout -5s must not be negative",
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		fakeClient := &pendingAPIClient{
+			fakeAPIClient: makeFakeClient(
+				0,
+				10*time.Second,
+				tagsForIdPrefix(prefix, faketag),
+				test.results,
+				params.ActionsByNames{},
+				"",
+			),
+			pending: test.pending,
+		}
+		restore := jujutesting.PatchValue(action.NewActionAPIClient,
+			func(c *action.ActionCommandBase) (action.APIClient, error) {
+				return fakeClient, nil
+			},
+		)
+
+		clock := testclock.NewClock(time.Now())
+		s.subcommand, _ = action.NewStatusCommandWithClockForTest(s.store, clock)
+		type runResult struct {
+			ctx *cmd.Context
+			err error
+		}
+		done := make(chan runResult, 1)
+		go func() {
+			ctx, err := cmdtesting.RunCommand(c, s.subcommand, "-m", "admin", test.wait, prefix)
+			done <- runResult{ctx, err}
+		}()
+		for j := 0; j < test.advances; j++ {
+			c.Assert(clock.WaitAdvance(test.advance, coretesting.LongWait, test.waiters), jc.ErrorIsNil)
+		}
+		var result runResult
+		select {
+		case result = <-done:
+		case <-time.After(coretesting.LongWait):
+			c.Fatalf("timed out waiting for the command to finish")
+		}
+		restore()
+
+		if test.expectError == "" {
+			c.Check(result.err, jc.ErrorIsNil)
+		} else {
+			c.Check(result.err, gc.ErrorMatches, test.expectError)
+		}
+		if test.expectStatus == "" {
+			continue
+		}
+		c.Check(result.ctx.Stdout.(*bytes.Buffer).String(), gc.Matches, "(?s).*status: "+test.expectStatus+"\n.*")
+	}
+}
+
+func (s *StatusSuite) TestInitInvalidStatus(c *gc.C) {
+	err := cmdtesting.InitCommand(s.subcommand, []string{"--status", "completed", "--status", "bogus"})
+	c.Assert(err, gc.ErrorMatches, `invalid status "bogus", expected one of: cancelled, completed, failed, pending, running`)
+}
+
+func (s *StatusSuite) TestRunFilterByStatus(c *gc.C) {
+	prefix := "deadbeef"
+	faketag := "action-" + prefix + "-0000-4000-8000-feedfacebeef"
+	faketag2 := "action-" + prefix + "-0001-4000-8000-feedfacebeef"
+	faketag3 := "action-" + prefix + "-0002-4000-8000-feedfacebeef"
+	completed := params.ActionResult{Status: params.ActionCompleted, Action: &params.Action{Tag: faketag, Name: "fakeName"}}
+	failed := params.ActionResult{Status: params.ActionFailed, Action: &params.Action{Tag: faketag2, Name: "fakeName"}}
+	running := params.ActionResult{Status: params.ActionRunning, Action: &params.Action{Tag: faketag3, Name: "fakeName"}}
+	results := []params.ActionResult{completed, failed, running}
+
+	for i, test := range []struct {
+		args   []string
+		expect []params.ActionResult
+	}{{
+		args:   []string{},
+		expect: results,
+	}, {
+		args:   []string{"--status", "failed"},
+		expect: []params.ActionResult{failed},
+	}, {
+		args:   []string{"--status", "failed", "--status", "running"},
+		expect: []params.ActionResult{failed, running},
+	}, {
+		args:   []string{"--status", "completed,running"},
+		expect: []params.ActionResult{completed, running},
+	}, {
+		args:   []string{"--status", "cancelled"},
+		expect: []params.ActionResult{},
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		fakeClient := makeFakeClient(
+			0*time.Second,
+			5*time.Second,
+			tagsForIdPrefix(prefix, faketag, faketag2, faketag3),
+			results,
+			params.ActionsByNames{},
+			"",
+		)
+		restore := s.patchAPIClient(fakeClient)
+
+		s.subcommand, _ = action.NewStatusCommandForTest(s.store)
+		args := append([]string{"-m", "admin", prefix}, test.args...)
+		ctx, err := cmdtesting.RunCommand(c, s.subcommand, args...)
+		restore()
+		c.Assert(err, jc.ErrorIsNil)
+
+		out := &bytes.Buffer{}
+		err = cmd.FormatYaml(out, action.ActionResultsToMap(test.expect))
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(ctx.Stdout.(*bytes.Buffer).String(), gc.Equals, out.String())
+	}
+}
+
+func (s *StatusSuite) TestRunWaitFilterByStatus(c *gc.C) {
+	prefix := "deadbeef"
+	faketag := "action-" + prefix + "-0000-4000-8000-feedfacebeef"
+	faketag2 := "action-" + prefix + "-0001-4000-8000-feedfacebeef"
+	completed := params.ActionResult{Status: params.ActionCompleted, Action: &params.Action{Tag: faketag, Name: "fakeName"}}
+	failed := params.ActionResult{Status: params.ActionFailed, Action: &params.Action{Tag: faketag2, Name: "fakeName"}}
+	fakeClient := makeFakeClient(
+		0*time.Second,
+		5*time.Second,
+		tagsForIdPrefix(prefix, faketag, faketag2),
+		[]params.ActionResult{completed, failed},
+		params.ActionsByNames{},
+		"",
+	)
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	// The failed action is filtered out, so it is neither shown nor
+	// counted as a failure.
+	ctx, err := cmdtesting.RunCommand(c, s.subcommand, "-m", "admin", "--wait", "--status", "completed", prefix)
+	c.Assert(err, jc.ErrorIsNil)
+	out := &bytes.Buffer{}
+	err = cmd.FormatYaml(out, action.ActionResultsToMap([]params.ActionResult{completed}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.Stdout.(*bytes.Buffer).String(), gc.Equals, out.String())
+}
+
+func (s *StatusSuite) TestRunFormatJSONTiming(c *gc.C) {
+	prefix := "deadbeef"
+	faketag := "action-" + prefix + "-0000-4000-8000-feedfacebeef"
+	faketag2 := "action-" + prefix + "-0001-4000-8000-feedfacebeef"
+	enqueued := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+	started := enqueued.Add(5 * time.Second)
+	completed := started.Add(90 * time.Second)
+	results := []params.ActionResult{{
+		Status:    params.ActionCompleted,
+		Action:    &params.Action{Tag: faketag, Name: "fakeName"},
+		Enqueued:  enqueued,
+		Started:   started,
+		Completed: completed,
+	}, {
+		Status:   params.ActionRunning,
+		Action:   &params.Action{Tag: faketag2, Name: "fakeName"},
+		Enqueued: enqueued,
+		Started:  started,
+	}}
+	fakeClient := makeFakeClient(
+		0*time.Second,
+		5*time.Second,
+		tagsForIdPrefix(prefix, faketag, faketag2),
+		results,
+		params.ActionsByNames{},
+		"",
+	)
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := cmdtesting.RunCommand(c, s.subcommand, "-m", "admin", "--format", "json", prefix)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var out struct {
+		Actions []struct {
+			Status string                 `json:"status"`
+			Timing map[string]interface{} `json:"timing"`
+		} `json:"actions"`
+	}
+	err = json.Unmarshal(ctx.Stdout.(*bytes.Buffer).Bytes(), &out)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out.Actions, gc.HasLen, 2)
+
+	c.Check(out.Actions[0].Status, gc.Equals, params.ActionCompleted)
+	c.Check(out.Actions[0].Timing, jc.DeepEquals, map[string]interface{}{
+		"enqueued":    "2019-05-01 10:00:00 +0000 UTC",
+		"started":     "2019-05-01 10:00:05 +0000 UTC",
+		"completed":   "2019-05-01 10:01:35 +0000 UTC",
+		"duration":    "1m30s",
+		"duration-ns": float64(90 * time.Second),
+	})
+
+	// No duration is given for an action that has not completed.
+	c.Check(out.Actions[1].Status, gc.Equals, params.ActionRunning)
+	c.Check(out.Actions[1].Timing, jc.DeepEquals, map[string]interface{}{
+		"enqueued": "2019-05-01 10:00:00 +0000 UTC",
+		"started":  "2019-05-01 10:00:05 +0000 UTC",
+	})
+}
+
+func (s *StatusSuite) TestRunDefaultFormatUnchanged(c *gc.C) {
+	prefix := "deadbeef"
+	faketag := "action-" + prefix + "-0000-4000-8000-feedfacebeef"
+	started := time.Date(2019, 5, 1, 10, 0, 0, 0, time.UTC)
+	results := []params.ActionResult{{
+		Status:    params.ActionCompleted,
+		Action:    &params.Action{Tag: faketag, Name: "fakeName"},
+		Enqueued:  started,
+		Started:   started,
+		Completed: started.Add(time.Minute),
+	}}
+	fakeClient := makeFakeClient(
+		0*time.Second,
+		5*time.Second,
+		tagsForIdPrefix(prefix, faketag),
+		results,
+		params.ActionsByNames{},
+		"",
+	)
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	ctx, err := cmdtesting.RunCommand(c, s.subcommand, "-m", "admin", prefix)
+	c.Assert(err, jc.ErrorIsNil)
+	out := &bytes.Buffer{}
+	err = cmd.FormatYaml(out, action.ActionResultsToMap(results))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.Stdout.(*bytes.Buffer).String(), gc.Equals, out.String())
+}
+
 func (s *StatusSuite) runTestCase(c *gc.C, tc statusTestCase) {
 	for _, modelFlag := range s.modelFlags {
 		fakeClient := makeFakeClient(
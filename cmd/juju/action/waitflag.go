@@ -10,18 +10,20 @@ import (
 // A gnuflag.Value for the --wait command line argument. If called
 // as a boolean  with no arguments, the forever flag is set to true.
 // If called  with an argument, d is set to the result of
-// time.ParseDuration().
+// time.ParseDuration(). Either way, set records that the flag was given.
 // eg:
 //   --wait
 //   --wait=10s
 type waitFlag struct {
 	forever bool
 	d       time.Duration
+	set     bool
 }
 
 func (f *waitFlag) Set(s string) error {
 	if s == "true" {
 		f.forever = true
+		f.set = true
 		return nil
 	}
 	v, err := time.ParseDuration(s)
@@ -29,6 +31,7 @@ func (f *waitFlag) Set(s string) error {
 		return err
 	}
 	f.d = v
+	f.set = true
 	return nil
 }
 
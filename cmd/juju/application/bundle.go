@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -41,6 +42,7 @@ import (
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/feature"
+	jujunames "github.com/juju/juju/juju/names"
 	"github.com/juju/juju/resource/resourceadapters"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/state/watcher"
@@ -105,7 +107,20 @@ func verifyBundle(data *charm.BundleData, bundleDir string) error {
 		}
 		return errors.New("the provided bundle has the following errors:\n" + strings.Join(errs, "\n"))
 	}
-	return errors.Trace(verifyError)
+	if verifyError != nil {
+		return errors.Trace(verifyError)
+	}
+	var reserved []string
+	for name := range data.Applications {
+		if jujunames.IsReservedName(name) {
+			reserved = append(reserved, name)
+		}
+	}
+	if len(reserved) > 0 {
+		sort.Strings(reserved)
+		return errors.Errorf("the provided bundle uses reserved application names: %s", strings.Join(reserved, ", "))
+	}
+	return nil
 }
 
 type bundleDeploySpec struct {
@@ -777,6 +777,18 @@ invalid constraints "bad-wolf" in application "mysql": malformed constraint "bad
 	err: `the provided bundle has the following errors:
 invalid constraints "bad-wolf" in application "mysql": malformed constraint "bad-wolf"
 negative number of units specified on application "mysql"`,
+}, {
+	about: "reserved application names",
+	content: `
+        applications:
+            juju-run:
+                charm: mysql
+                num_units: 1
+            jujud:
+                charm: mysql
+                num_units: 1
+    `,
+	err: `the provided bundle uses reserved application names: juju-run, jujud`,
 }, {
 	about: "bundle inception",
 	content: `
@@ -47,6 +47,7 @@ import (
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/environs/config"
+	jujunames "github.com/juju/juju/juju/names"
 	"github.com/juju/juju/resource/resourceadapters"
 	"github.com/juju/juju/storage"
 )
@@ -736,6 +737,9 @@ func (c *DeployCommand) Init(args []string) error {
 		if !names.IsValidApplication(args[1]) {
 			return errors.Errorf("invalid application name %q", args[1])
 		}
+		if jujunames.IsReservedName(args[1]) {
+			return errors.Errorf("application name %q is reserved", args[1])
+		}
 		c.ApplicationName = args[1]
 		fallthrough
 	case 1:
@@ -929,7 +933,7 @@ func (c *DeployCommand) deployCharm(
 	// Storage cannot be added to a container.
 	if len(c.Storage) > 0 || len(c.AttachStorage) > 0 {
 		for _, placement := range c.Placement {
-			if t, err := instance.ParseContainerType(placement.Scope); err == nil {
+			if t := instance.ContainerType(placement.Scope); instance.IsSupportedContainerType(t) {
 				return errors.NotSupportedf("adding storage to %s container", string(t))
 			}
 		}
@@ -950,6 +954,9 @@ func (c *DeployCommand) deployCharm(
 	if applicationName == "" {
 		applicationName = charmInfo.Meta.Name
 	}
+	if jujunames.IsReservedName(applicationName) {
+		return errors.Errorf("application name %q is reserved", applicationName)
+	}
 
 	// Process the --config args.
 	// We may have a single file arg specified, in which case
@@ -209,6 +209,9 @@ var initErrorTests = []struct {
 	}, {
 		args: []string{"craziness", "burble-1"},
 		err:  `invalid application name "burble-1"`,
+	}, {
+		args: []string{"craziness", "jujud"},
+		err:  `application name "jujud" is reserved`,
 	}, {
 		args: []string{"craziness", "burble1", "-n", "0"},
 		err:  `--num-units must be a positive integer`,
@@ -274,6 +277,19 @@ func (s *DeploySuite) TestCharmDir(c *gc.C) {
 	s.AssertApplication(c, "multi-series", curl, 1, 0)
 }
 
+func (s *DeploySuite) TestCharmDirReservedName(c *gc.C) {
+	ch := testcharms.RepoWithSeries("bionic").ClonedDirPath(s.CharmsPath, "multi-series")
+	metadataPath := filepath.Join(ch, "metadata.yaml")
+	metadata, err := ioutil.ReadFile(metadataPath)
+	c.Assert(err, jc.ErrorIsNil)
+	metadata = []byte(strings.Replace(string(metadata), "name: multi-series", "name: jujud", 1))
+	err = ioutil.WriteFile(metadataPath, metadata, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.runDeploy(c, ch, "--series", "trusty")
+	c.Assert(err, gc.ErrorMatches, `application name "jujud" is reserved`)
+}
+
 func (s *DeploySuite) TestDeployFromPathRelativeDir(c *gc.C) {
 	testcharms.RepoWithSeries("bionic").ClonedDirPath(s.CharmsPath, "multi-series")
 	wd, err := os.Getwd()
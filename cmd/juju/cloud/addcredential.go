@@ -470,9 +470,9 @@ func (c *addCredentialCommand) promptFieldValue(p *interact.Pollster, attr jujuc
 	case attr.Hidden:
 		return p.EnterPassword(name)
 	case attr.ExpandFilePath:
-		return enterFile(name, attr.Description, p, true, attr.Optional)
+		return enterFile(name, attr.Description, p, true, attr.Optional, false)
 	case attr.FilePath:
-		return enterFile(name, attr.Description, p, false, attr.Optional)
+		return enterFile(name, attr.Description, p, false, attr.Optional, attr.AllowInlineJSON)
 	case attr.Optional:
 		return p.EnterOptional(name)
 	default:
@@ -480,7 +480,9 @@ func (c *addCredentialCommand) promptFieldValue(p *interact.Pollster, attr jujuc
 	}
 }
 
-func enterFile(name, descr string, p *interact.Pollster, expanded, optional bool) (string, error) {
+// enterFile prompts for the path of a file. If inlineJSON is true, a JSON
+// document may be entered instead, and is returned as is.
+func enterFile(name, descr string, p *interact.Pollster, expanded, optional, inlineJSON bool) (string, error) {
 	inputSuffix := ""
 	if optional {
 		inputSuffix += " (optional)"
@@ -489,6 +491,9 @@ func enterFile(name, descr string, p *interact.Pollster, expanded, optional bool
 		if optional && s == "" {
 			return true, "", nil
 		}
+		if inlineJSON && jujucloud.IsInlineJSON(s) {
+			return true, "", nil
+		}
 		_, err = jujucloud.ValidateFileAttrValue(s)
 		if err != nil {
 			return false, err.Error(), nil
@@ -504,6 +509,9 @@ func enterFile(name, descr string, p *interact.Pollster, expanded, optional bool
 	if optional && input == "" {
 		return "", nil
 	}
+	if inlineJSON && jujucloud.IsInlineJSON(input) {
+		return input, nil
+	}
 
 	// We have to run this twice, since it has glommed together
 	// validation and normalization, and Pollster doesn't deal with the
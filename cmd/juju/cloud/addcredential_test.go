@@ -468,6 +468,34 @@ func (s *addCredentialSuite) TestAddJsonFileCredential(c *gc.C) {
 	s.assertAddFileCredential(c, "fred\nbadfile\n.\n%s\n", "file")
 }
 
+func (s *addCredentialSuite) TestAddJsonFileCredentialInline(c *gc.C) {
+	s.authTypes = []jujucloud.AuthType{jujucloud.JSONFileAuthType}
+	s.schema = map[jujucloud.AuthType]jujucloud.CredentialSchema{
+		jujucloud.JSONFileAuthType: {
+			{
+				"file",
+				jujucloud.CredentialAttr{
+					FilePath:        true,
+					AllowInlineJSON: true,
+				},
+			},
+		},
+	}
+	contents := `{"type": "service_account"}`
+	stdin := strings.NewReader("fred\n" + contents + "\n")
+	_, err := s.run(c, stdin, "somecloud")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.store.Credentials, jc.DeepEquals, map[string]jujucloud.CloudCredential{
+		"somecloud": {
+			AuthCredentials: map[string]jujucloud.Credential{
+				"fred": jujucloud.NewCredential(jujucloud.JSONFileAuthType, map[string]string{
+					"file": contents,
+				}),
+			},
+		},
+	})
+}
+
 func (s *addCredentialSuite) TestAddCredentialWithFileAttr(c *gc.C) {
 	s.authTypes = []jujucloud.AuthType{jujucloud.UserPassAuthType}
 	s.schema = map[jujucloud.AuthType]jujucloud.CredentialSchema{
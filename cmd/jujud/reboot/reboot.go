@@ -116,6 +116,10 @@ func (r *Reboot) runningContainers() ([]instances.Instance, error) {
 		}
 		cfg := managerConfig
 		manager, err := factory.NewContainerManager(val, cfg)
+		if errors.IsNotSupported(err) {
+			logger.Infof("container type %q not supported", val)
+			continue
+		}
 		if err != nil {
 			return nil, errors.Annotatef(err, "failed to get manager for container type %v", val)
 		}
@@ -8,6 +8,7 @@ import (
 	"net"
 	"path/filepath"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -17,6 +18,8 @@ import (
 	"gopkg.in/juju/charm.v6"
 	"gopkg.in/juju/names.v2"
 
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/common"
 	apiprovisioner "github.com/juju/juju/api/provisioner"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cloudconfig"
@@ -25,6 +28,7 @@ import (
 	"github.com/juju/juju/container/broker"
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/machinelock"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/context"
@@ -47,6 +51,30 @@ func (s *brokerSuite) SetUpSuite(c *gc.C) {
 	broker.PatchNewMachineInitReader(s, newFakeMachineInitReader)
 }
 
+func (s *brokerSuite) TestNewUnsupportedContainerType(c *gc.C) {
+	_, err := broker.New(broker.Config{
+		Name:          "provisioner",
+		ContainerType: instance.PODMAN,
+		APICaller:     NewFakeAPI(),
+		AgentConfig:   fakeAgentConfig{},
+		MachineTag:    names.NewMachineTag("0"),
+		MachineLock:   fakeMachineLock{},
+		GetNetConfig: func(common.NetworkConfigSource) ([]params.NetworkConfig, error) {
+			return nil, nil
+		},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "podman containers not supported")
+}
+
+func (s *brokerSuite) TestSupportsContainerType(c *gc.C) {
+	for _, t := range instance.SupportedContainerTypes() {
+		c.Check(broker.SupportsContainerType(t), jc.IsTrue, gc.Commentf("%s", t))
+	}
+	c.Assert(broker.SupportsContainerType(instance.PODMAN), jc.IsFalse)
+	c.Assert(broker.SupportsContainerType(instance.NONE), jc.IsFalse)
+}
+
 func (s *brokerSuite) TestCombinedCloudInitDataNoCloudInitUserData(c *gc.C) {
 	obtained, err := broker.CombinedCloudInitData(nil, "ca-certs,apt-primary", "xenial", loggo.Logger{})
 	c.Assert(err, jc.ErrorIsNil)
@@ -396,3 +424,11 @@ var newFakeMachineInitReader = func(series string) (cloudconfig.InitReader, erro
 	r, err := cloudconfig.NewMachineInitReader(series)
 	return &fakeMachineInitReader{r}, err
 }
+
+type fakeAgentConfig struct {
+	agent.Config
+}
+
+type fakeMachineLock struct {
+	machinelock.Lock
+}
@@ -76,27 +76,36 @@ func (c Config) Validate() error {
 // so that we can create them with the same arguments.
 type ContainerBrokerFunc func(PrepareHostFunc, APICalls, container.Manager, agent.Config) (environs.InstanceBroker, error)
 
+// containerBrokers holds the broker constructor for each container type
+// that can be provisioned.
+var containerBrokers = map[instance.ContainerType]ContainerBrokerFunc{
+	instance.KVM: NewKVMBroker,
+	instance.LXD: NewLXDBroker,
+}
+
+// SupportsContainerType reports whether New can create a broker for
+// containers of the given type.
+func SupportsContainerType(containerType instance.ContainerType) bool {
+	_, ok := containerBrokers[containerType]
+	return ok
+}
+
 // New creates a new InstanceBroker from the Config
 func New(config Config) (environs.InstanceBroker, error) {
 	if err := config.Validate(); err != nil {
 		return nil, errors.Trace(err)
 	}
 
+	newBroker, ok := containerBrokers[config.ContainerType]
+	if !ok {
+		return nil, errors.NotSupportedf("%s containers", config.ContainerType)
+	}
+
 	manager, err := factory.NewContainerManager(config.ContainerType, config.ManagerConfig)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	var newBroker ContainerBrokerFunc
-	switch config.ContainerType {
-	case instance.KVM:
-		newBroker = NewKVMBroker
-	case instance.LXD:
-		newBroker = NewLXDBroker
-	default:
-		return nil, errors.NotValidf("ContainerType %s", config.ContainerType)
-	}
-
 	broker, err := newBroker(prepareHost(config), config.APICaller, manager, config.AgentConfig)
 	if err != nil {
 		logger.Errorf("failed to create new %s broker", config.ContainerType)
@@ -26,6 +26,8 @@ var NewContainerManager = func(forType instance.ContainerType, conf container.Ma
 		return lxd.NewContainerManager(conf, svr)
 	case instance.KVM:
 		return kvm.NewContainerManager(conf)
+	case instance.PODMAN:
+		return nil, errors.NotSupportedf("%s container manager", forType)
 	}
 	return nil, errors.Errorf("unknown container type: %q", forType)
 }
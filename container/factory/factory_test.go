@@ -4,6 +4,7 @@
 package factory_test
 
 import (
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -50,3 +51,11 @@ func (*factorySuite) TestNewContainerManager(c *gc.C) {
 		}
 	}
 }
+
+func (*factorySuite) TestNewContainerManagerPodmanNotSupported(c *gc.C) {
+	conf := container.ManagerConfig{container.ConfigModelUUID: testing.ModelTag.Id()}
+	manager, err := factory.NewContainerManager(instance.PODMAN, conf)
+	c.Assert(err, gc.ErrorMatches, `podman container manager not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(manager, gc.IsNil)
+}
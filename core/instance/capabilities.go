@@ -0,0 +1,60 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instance
+
+import (
+	"github.com/juju/errors"
+)
+
+// ContainerTypeCapabilities describes what juju supports for a
+// container type.
+type ContainerTypeCapabilities struct {
+	// NestedTypes holds the types of container that juju can create
+	// inside a container of this type.
+	NestedTypes []ContainerType
+}
+
+// CanHost reports whether a container of type t may be created inside
+// a container with these capabilities.
+func (c ContainerTypeCapabilities) CanHost(t ContainerType) bool {
+	for _, nested := range c.NestedTypes {
+		if t == nested {
+			return true
+		}
+	}
+	return false
+}
+
+// containerTypeCapabilities holds the capabilities of each supported
+// container type. LXD containers may be nested in LXD containers and in
+// KVM guests. KVM guests may also be nested in KVM guests, on hosts with
+// nested virtualisation enabled, but not in LXD containers. Podman
+// containers cannot host anything.
+var containerTypeCapabilities = map[ContainerType]ContainerTypeCapabilities{
+	LXD:    {NestedTypes: []ContainerType{LXD}},
+	KVM:    {NestedTypes: []ContainerType{LXD, KVM}},
+	PODMAN: {},
+}
+
+// Capabilities returns the capabilities of the container type. Types
+// that are not supported have no capabilities.
+func Capabilities(t ContainerType) ContainerTypeCapabilities {
+	return containerTypeCapabilities[t]
+}
+
+// SupportsNesting reports whether any type of container may be created
+// inside a container of type t.
+func SupportsNesting(t ContainerType) bool {
+	return len(Capabilities(t).NestedTypes) > 0
+}
+
+// ValidateNesting returns an error satisfying errors.IsNotSupported if a
+// container of type nested cannot be created inside a container of type
+// host.
+func ValidateNesting(host, nested ContainerType) error {
+	if !Capabilities(host).CanHost(nested) {
+		return errors.NotSupportedf("%s container inside %s container", nested, host)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package instance_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/instance"
+)
+
+type CapabilitiesSuite struct{}
+
+var _ = gc.Suite(&CapabilitiesSuite{})
+
+func (s *CapabilitiesSuite) TestSupportsNesting(c *gc.C) {
+	c.Assert(instance.SupportsNesting(instance.LXD), jc.IsTrue)
+	c.Assert(instance.SupportsNesting(instance.KVM), jc.IsTrue)
+	c.Assert(instance.SupportsNesting(instance.PODMAN), jc.IsFalse)
+	c.Assert(instance.SupportsNesting(instance.NONE), jc.IsFalse)
+	c.Assert(instance.SupportsNesting("omg"), jc.IsFalse)
+}
+
+func (s *CapabilitiesSuite) TestAllSupportedTypesHaveCapabilities(c *gc.C) {
+	for _, t := range instance.SupportedContainerTypes() {
+		_, ok := instance.ContainerTypeCapabilitiesTable[t]
+		c.Check(ok, jc.IsTrue, gc.Commentf("no capabilities for %s", t))
+	}
+}
+
+func (s *CapabilitiesSuite) TestNestedTypesAreSupported(c *gc.C) {
+	for _, t := range instance.SupportedContainerTypes() {
+		for _, nested := range instance.Capabilities(t).NestedTypes {
+			c.Check(instance.IsSupportedContainerType(nested), jc.IsTrue,
+				gc.Commentf("%s nested in %s", nested, t))
+		}
+	}
+}
+
+func (s *CapabilitiesSuite) TestValidateNesting(c *gc.C) {
+	for i, test := range []struct {
+		host, nested instance.ContainerType
+		valid        bool
+	}{
+		{instance.LXD, instance.LXD, true},
+		{instance.LXD, instance.KVM, false},
+		{instance.LXD, instance.PODMAN, false},
+		{instance.KVM, instance.LXD, true},
+		{instance.KVM, instance.KVM, true},
+		{instance.KVM, instance.PODMAN, false},
+		{instance.PODMAN, instance.LXD, false},
+		{instance.PODMAN, instance.PODMAN, false},
+	} {
+		c.Logf("test %d: %s in %s", i, test.nested, test.host)
+		err := instance.ValidateNesting(test.host, test.nested)
+		if test.valid {
+			c.Check(err, jc.ErrorIsNil)
+			continue
+		}
+		c.Check(err, jc.Satisfies, errors.IsNotSupported)
+		c.Check(err, gc.ErrorMatches, string(test.nested)+" container inside "+string(test.host)+" container not supported")
+	}
+}
@@ -12,18 +12,64 @@ type ContainerType string
 
 // Known container types.
 const (
-	NONE ContainerType = "none"
-	LXD  ContainerType = "lxd"
-	KVM  ContainerType = "kvm"
+	NONE   ContainerType = "none"
+	LXD    ContainerType = "lxd"
+	KVM    ContainerType = "kvm"
+	PODMAN ContainerType = "podman"
 )
 
-// ContainerTypes is used to validate add-machine arguments.
+// ContainerTypes is used to validate add-machine arguments. It holds
+// the container types that machine agents can provision.
 var ContainerTypes = []ContainerType{
 	LXD,
 	KVM,
 }
 
-// ParseContainerTypeOrNone converts the specified string into a supported
+// unsupportedContainerTypes holds container types that are recognised
+// when parsing, but cannot be provisioned yet. PODMAN moves into
+// ContainerTypes once the container broker can create podman
+// containers.
+var unsupportedContainerTypes = []ContainerType{
+	PODMAN,
+}
+
+// SupportedContainerTypes returns the container types that juju can
+// provision. "none" is not included. The result may be modified by the
+// caller.
+func SupportedContainerTypes() []ContainerType {
+	result := make([]ContainerType, len(ContainerTypes))
+	copy(result, ContainerTypes)
+	return result
+}
+
+// IsSupportedContainerType reports whether t is one of the container
+// types that juju can provision.
+func IsSupportedContainerType(t ContainerType) bool {
+	return containsContainerType(ContainerTypes, t)
+}
+
+func containsContainerType(types []ContainerType, t ContainerType) bool {
+	for _, ct := range types {
+		if t == ct {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the name of the container type. For every value
+// returned by ParseContainerTypeOrNone, parsing the name gives back the
+// same value. The zero value has an empty name.
+func (t ContainerType) String() string {
+	return string(t)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t ContainerType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// ParseContainerTypeOrNone converts the specified string into a known
 // ContainerType instance or returns an error if the container type is invalid.
 // For this version of the function, 'none' is a valid value.
 func ParseContainerTypeOrNone(ctype string) (ContainerType, error) {
@@ -33,13 +79,15 @@ func ParseContainerTypeOrNone(ctype string) (ContainerType, error) {
 	return ParseContainerType(ctype)
 }
 
-// ParseContainerType converts the specified string into a supported
+// ParseContainerType converts the specified string into a known
 // ContainerType instance or returns an error if the container type is invalid.
+// Container types that are recognised but not yet supported, such as
+// podman, are returned without error; use IsSupportedContainerType to
+// check that juju can provision the result.
 func ParseContainerType(ctype string) (ContainerType, error) {
-	for _, supportedType := range ContainerTypes {
-		if ContainerType(ctype) == supportedType {
-			return supportedType, nil
-		}
+	t := ContainerType(ctype)
+	if !IsSupportedContainerType(t) && !containsContainerType(unsupportedContainerTypes, t) {
+		return "", fmt.Errorf("invalid container type %q", ctype)
 	}
-	return "", fmt.Errorf("invalid container type %q", ctype)
+	return ContainerType(ctype), nil
 }
@@ -4,10 +4,12 @@
 package instance_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/core/instance"
 )
@@ -33,6 +35,10 @@ func (s *InstanceSuite) TestParseContainerType(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(ctype, gc.Equals, instance.KVM)
 
+	ctype, err = instance.ParseContainerType("podman")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctype, gc.Equals, instance.PODMAN)
+
 	_, err = instance.ParseContainerType("none")
 	c.Assert(err, gc.ErrorMatches, `invalid container type "none"`)
 
@@ -40,6 +46,33 @@ func (s *InstanceSuite) TestParseContainerType(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid container type "omg"`)
 }
 
+func (s *InstanceSuite) TestSupportedContainerTypes(c *gc.C) {
+	types := instance.SupportedContainerTypes()
+	c.Assert(types, jc.SameContents, []instance.ContainerType{instance.LXD, instance.KVM})
+
+	// Modifying the result does not affect the canonical list.
+	types[0] = "omg"
+	c.Assert(instance.SupportedContainerTypes(), gc.Not(jc.DeepEquals), types)
+}
+
+func (s *InstanceSuite) TestSupportedContainerTypesRoundTrip(c *gc.C) {
+	for _, t := range instance.SupportedContainerTypes() {
+		c.Check(instance.IsSupportedContainerType(t), jc.IsTrue)
+		ctype, err := instance.ParseContainerType(string(t))
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(ctype, gc.Equals, t)
+	}
+}
+
+func (s *InstanceSuite) TestIsSupportedContainerType(c *gc.C) {
+	c.Assert(instance.IsSupportedContainerType(instance.LXD), jc.IsTrue)
+	c.Assert(instance.IsSupportedContainerType(instance.KVM), jc.IsTrue)
+	c.Assert(instance.IsSupportedContainerType(instance.PODMAN), jc.IsFalse)
+	c.Assert(instance.IsSupportedContainerType(instance.NONE), jc.IsFalse)
+	c.Assert(instance.IsSupportedContainerType(""), jc.IsFalse)
+	c.Assert(instance.IsSupportedContainerType("omg"), jc.IsFalse)
+}
+
 func (s *InstanceSuite) TestParseContainerTypeOrNone(c *gc.C) {
 	ctype, err := instance.ParseContainerTypeOrNone("lxd")
 	c.Assert(err, jc.ErrorIsNil)
@@ -53,6 +86,10 @@ func (s *InstanceSuite) TestParseContainerTypeOrNone(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(ctype, gc.Equals, instance.KVM)
 
+	ctype, err = instance.ParseContainerTypeOrNone("podman")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctype, gc.Equals, instance.PODMAN)
+
 	ctype, err = instance.ParseContainerTypeOrNone("none")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(ctype, gc.Equals, instance.NONE)
@@ -60,3 +97,74 @@ func (s *InstanceSuite) TestParseContainerTypeOrNone(c *gc.C) {
 	_, err = instance.ParseContainerTypeOrNone("omg")
 	c.Assert(err, gc.ErrorMatches, `invalid container type "omg"`)
 }
+
+func (s *InstanceSuite) TestContainerTypeRoundTrip(c *gc.C) {
+	for _, ctype := range []instance.ContainerType{
+		instance.NONE,
+		instance.LXD,
+		instance.KVM,
+		instance.PODMAN,
+	} {
+		c.Logf("container type %q", ctype)
+
+		parsed, err := instance.ParseContainerTypeOrNone(ctype.String())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(parsed, gc.Equals, ctype)
+
+		text, err := ctype.MarshalText()
+		c.Assert(err, jc.ErrorIsNil)
+		parsed, err = instance.ParseContainerTypeOrNone(string(text))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(parsed, gc.Equals, ctype)
+	}
+}
+
+func (s *InstanceSuite) TestContainerTypeSerialisation(c *gc.C) {
+	type doc struct {
+		Type instance.ContainerType `json:"type" yaml:"type"`
+	}
+	for i, test := range []struct {
+		in   doc
+		json string
+		yaml string
+	}{{
+		in:   doc{Type: instance.LXD},
+		json: `{"type":"lxd"}`,
+		yaml: "type: lxd\n",
+	}, {
+		in:   doc{Type: instance.NONE},
+		json: `{"type":"none"}`,
+		yaml: "type: none\n",
+	}, {
+		in:   doc{},
+		json: `{"type":""}`,
+		yaml: "type: \"\"\n",
+	}} {
+		c.Logf("test %d: %q", i, test.in.Type)
+
+		data, err := json.Marshal(test.in)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(data), gc.Equals, test.json)
+		var fromJSON doc
+		err = json.Unmarshal(data, &fromJSON)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(fromJSON, gc.Equals, test.in)
+
+		data, err = yaml.Marshal(test.in)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(data), gc.Equals, test.yaml)
+		var fromYAML doc
+		err = yaml.Unmarshal(data, &fromYAML)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(fromYAML, gc.Equals, test.in)
+	}
+}
+
+func (s *InstanceSuite) TestContainerTypeUnmarshalJSONUnknown(c *gc.C) {
+	var doc struct {
+		Type instance.ContainerType `json:"type"`
+	}
+	err := json.Unmarshal([]byte(`{"type":"omg"}`), &doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc.Type, gc.Equals, instance.ContainerType("omg"))
+}
@@ -40,8 +40,7 @@ func (p *Placement) String() string {
 }
 
 func isContainerType(s string) bool {
-	_, err := ParseContainerType(s)
-	return err == nil
+	return IsSupportedContainerType(ContainerType(s))
 }
 
 // ParsePlacement attempts to parse the specified string and create a
@@ -154,6 +154,17 @@ type RequestFinalizeCredential interface {
 	ShouldFinalizeCredential(cloud.Credential) bool
 }
 
+// CredentialChecker is an interface that an EnvironProvider may implement
+// in order to check that a credential is accepted by the cloud and may be
+// used to manage models. The controller calls it when validating a
+// credential for a model, such as when the credential is updated.
+type CredentialChecker interface {
+	// CheckCredential checks the credential in the given cloud spec
+	// against the cloud, returning an error describing why it cannot
+	// be used if it is rejected.
+	CheckCredential(CloudSpec) error
+}
+
 // FinalizeCredentialContext is an interface passed into FinalizeCredential
 // to provide a means of interacting with the user when finalizing credentials.
 type FinalizeCredentialContext interface {
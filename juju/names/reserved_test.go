@@ -0,0 +1,44 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package names_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/names"
+)
+
+type reservedSuite struct{}
+
+var _ = gc.Suite(&reservedSuite{})
+
+func (*reservedSuite) TestIsReservedName(c *gc.C) {
+	for _, name := range []string{
+		"juju",
+		"jujud",
+		"jujud.exe",
+		"juju-run",
+		"juju-run.exe",
+		"juju-dumplogs",
+		"juju-introspect",
+		"juju-updateseries",
+	} {
+		c.Logf("checking %q", name)
+		c.Check(names.IsReservedName(name), jc.IsTrue)
+	}
+}
+
+func (*reservedSuite) TestIsReservedNameNotReserved(c *gc.C) {
+	for _, name := range []string{
+		"",
+		"mysql",
+		"jujud-versions.yaml",
+		"juju-gui",
+		"jujud2",
+	} {
+		c.Logf("checking %q", name)
+		c.Check(names.IsReservedName(name), jc.IsFalse)
+	}
+}
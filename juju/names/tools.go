@@ -0,0 +1,32 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package names
+
+import "strings"
+
+// AllToolNames returns the names of all the juju tool binaries for the
+// current platform.
+func AllToolNames() []string {
+	return []string{
+		Juju,
+		Jujud,
+		JujuRun,
+		JujuDumpLogs,
+		JujuIntrospect,
+		JujuUpdateSeries,
+	}
+}
+
+// IsReservedName reports whether name is the name of a juju tool binary
+// on any platform, and so should not be used for things such as
+// applications.
+func IsReservedName(name string) bool {
+	name = strings.TrimSuffix(name, ".exe")
+	for _, tool := range AllToolNames() {
+		if name == strings.TrimSuffix(tool, ".exe") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package names_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/names"
+)
+
+type toolsSuite struct{}
+
+var _ = gc.Suite(&toolsSuite{})
+
+func (*toolsSuite) TestAllToolNames(c *gc.C) {
+	c.Assert(names.AllToolNames(), jc.SameContents, []string{
+		"juju.exe",
+		"jujud.exe",
+		"juju-run.exe",
+		"juju-dumplogs.exe",
+		"juju-introspect.exe",
+		"juju-updateseries.exe",
+	})
+}
@@ -4,11 +4,16 @@
 package gce
 
 import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils"
@@ -24,8 +29,19 @@ const (
 	credAttrClientEmail = "client-email"
 	credAttrProjectID   = "project-id"
 
-	// The contents of the file for "jsonfile" auth-type.
+	// The contents of the file for "jsonfile" auth-type, which may
+	// also be given inline.
 	credAttrFile = "file"
+
+	// The impersonated account and its optional delegation chain
+	// for "service-account-impersonation" auth-type.
+	credAttrTargetServiceAccount = "target-service-account"
+	credAttrDelegates            = "delegates"
+
+	// envImpersonateServiceAccount is the gcloud environment variable
+	// naming the service account (optionally preceded by a comma
+	// separated delegation chain) to impersonate.
+	envImpersonateServiceAccount = "CLOUDSDK_AUTH_IMPERSONATE_SERVICE_ACCOUNT"
 )
 
 type environProviderCredentials struct{}
@@ -52,8 +68,32 @@ func (environProviderCredentials) CredentialSchemas() map[cloud.AuthType]cloud.C
 		cloud.JSONFileAuthType: {{
 			Name: credAttrFile,
 			CredentialAttr: cloud.CredentialAttr{
-				Description: "path to the .json file containing a service account key for your project\n(detailed instructions available at https://discourse.jujucharms.com/t/1508).\nPath",
-				FilePath:    true,
+				Description:     "path to the .json file containing a service account key for your project,\nor the key itself as inline JSON\n(detailed instructions available at https://discourse.jujucharms.com/t/1508).\nPath or JSON",
+				FilePath:        true,
+				AllowInlineJSON: true,
+			},
+		}},
+		cloud.ServiceAccountImpersonationAuthType: {{
+			Name: credAttrFile,
+			CredentialAttr: cloud.CredentialAttr{
+				Description:     "path to the .json file containing the service account key used to\nrequest short-lived tokens for the impersonated service account,\nor the key itself as inline JSON.\nPath or JSON",
+				FilePath:        true,
+				AllowInlineJSON: true,
+			},
+		}, {
+			Name:           credAttrTargetServiceAccount,
+			CredentialAttr: cloud.CredentialAttr{Description: "e-mail address of the service account to impersonate"},
+		}, {
+			Name: credAttrDelegates,
+			CredentialAttr: cloud.CredentialAttr{
+				Description: "comma separated e-mail addresses of the service accounts in the delegation chain",
+				Optional:    true,
+			},
+		}, {
+			Name: credAttrProjectID,
+			CredentialAttr: cloud.CredentialAttr{
+				Description: "project ID (defaults to the project of the service account key)",
+				Optional:    true,
 			},
 		}},
 	}
@@ -66,6 +106,10 @@ func (environProviderCredentials) DetectCredentials() (*cloud.CloudCredential, e
 	// 2. whose location is known to the gcloud command-line tool.
 	//   On Windows, this is %APPDATA%/gcloud/application_default_credentials.json.
 	//   On other systems, $HOME/.config/gcloud/application_default_credentials.json.
+	// If gcloud is configured to impersonate a service account, the key
+	// in that file is used as the source of the impersonated credential.
+	// The default project, region and zone are taken from the gcloud
+	// configuration or, on a GCE instance, the metadata server.
 
 	validatePath := func(possibleFilePath string) string {
 		if possibleFilePath == "" {
@@ -108,9 +152,32 @@ func (environProviderCredentials) DetectCredentials() (*cloud.CloudCredential, e
 	if credName == "" {
 		credName = parsedCred.Attributes()[credAttrClientID]
 	}
+	if chain := splitDelegates(os.Getenv(envImpersonateServiceAccount)); len(chain) > 0 {
+		target := chain[len(chain)-1]
+		attrs := map[string]string{
+			credAttrFile:                 possibleFilePath,
+			credAttrTargetServiceAccount: target,
+		}
+		if delegates := chain[:len(chain)-1]; len(delegates) > 0 {
+			attrs[credAttrDelegates] = strings.Join(delegates, ",")
+		}
+		cred = cloud.NewCredential(cloud.ServiceAccountImpersonationAuthType, attrs)
+		credName = target
+	}
+	defaults := detectGcloudDefaults()
 	cred.Label = fmt.Sprintf("google credential %q", credName)
+	var details []string
+	if defaults.project != "" {
+		details = append(details, fmt.Sprintf("project %q", defaults.project))
+	}
+	if defaults.zone != "" {
+		details = append(details, fmt.Sprintf("zone %q", defaults.zone))
+	}
+	if len(details) > 0 {
+		cred.Label += " (" + strings.Join(details, ", ") + ")"
+	}
 	return &cloud.CloudCredential{
-		DefaultRegion: os.Getenv("CLOUDSDK_COMPUTE_REGION"),
+		DefaultRegion: defaults.region,
 		AuthCredentials: map[string]cloud.Credential{
 			user: cred,
 		}}, nil
@@ -130,6 +197,9 @@ func parseJSONAuthFile(r io.Reader) (cloud.Credential, error) {
 	if err != nil {
 		return cloud.Credential{}, errors.Trace(err)
 	}
+	if err := validatePrivateKey(string(creds.PrivateKey)); err != nil {
+		return cloud.Credential{}, errors.Trace(err)
+	}
 	return cloud.NewCredential(cloud.OAuth2AuthType, map[string]string{
 		credAttrProjectID:   creds.ProjectID,
 		credAttrClientID:    creds.ClientID,
@@ -138,7 +208,163 @@ func parseJSONAuthFile(r io.Reader) (cloud.Credential, error) {
 	}), nil
 }
 
+// jsonKeyRequiredFields are the fields of a service account key that
+// must be set for it to be used as a "jsonfile" credential.
+var jsonKeyRequiredFields = []string{"client_email", "private_key", "project_id"}
+
+// validateJSONKey checks that the contents of a service account key are
+// JSON with all of the required fields set. The project ID may be left
+// out of the key if requireProject is false, as when a
+// service-account-impersonation credential specifies the project.
+func validateJSONKey(contents string, requireProject bool) error {
+	var key map[string]interface{}
+	if err := json.Unmarshal([]byte(contents), &key); err != nil {
+		return errors.NewNotValid(err, "service account key is not valid JSON")
+	}
+	var missing []string
+	for _, field := range jsonKeyRequiredFields {
+		if field == "project_id" && !requireProject {
+			continue
+		}
+		if value, _ := key[field].(string); value == "" {
+			missing = append(missing, fmt.Sprintf("%q", field))
+		}
+	}
+	if len(missing) > 0 {
+		return errors.NewNotValid(nil, fmt.Sprintf("service account key missing %s", strings.Join(missing, ", ")))
+	}
+	return nil
+}
+
+// impersonationCredentialAttributes returns the attributes of the source
+// account whose key is held in the "file" attribute of a
+// service-account-impersonation credential, together with the account
+// to impersonate. The source account's project is used unless a
+// project ID is specified.
+func impersonationCredentialAttributes(attrs map[string]string) (map[string]string, error) {
+	if err := validateJSONKey(attrs[credAttrFile], attrs[credAttrProjectID] == ""); err != nil {
+		return nil, errors.Trace(err)
+	}
+	source, err := parseJSONAuthFile(strings.NewReader(attrs[credAttrFile]))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := source.Attributes()
+	result[credAttrTargetServiceAccount] = attrs[credAttrTargetServiceAccount]
+	result[credAttrDelegates] = attrs[credAttrDelegates]
+	if projectID := attrs[credAttrProjectID]; projectID != "" {
+		result[credAttrProjectID] = projectID
+	}
+	return result, nil
+}
+
+// splitDelegates splits a comma separated list of service accounts,
+// ignoring surrounding whitespace and empty entries.
+func splitDelegates(value string) []string {
+	var accounts []string
+	for _, account := range strings.Split(value, ",") {
+		if account = strings.TrimSpace(account); account != "" {
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts
+}
+
+// validatePrivateKey checks that the given private key is a PEM encoded
+// RSA key, as needed to sign the tokens used to authenticate with GCE.
+func validatePrivateKey(key string) error {
+	notValid := func(err error) error {
+		return errors.NewNotValid(err, fmt.Sprintf("%q is not a PEM encoded RSA private key", credAttrPrivateKey))
+	}
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return notValid(nil)
+	}
+	if _, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return notValid(err)
+	}
+	if _, ok := parsed.(*rsa.PrivateKey); !ok {
+		return notValid(errors.Errorf("unexpected key type %T", parsed))
+	}
+	return nil
+}
+
+// CheckCredential is part of the environs.CredentialChecker interface.
+// It makes a lightweight request to the GCE API with the credential,
+// distinguishing a key that is rejected from an account that lacks the
+// permissions needed to use the project, and from a project that refuses
+// requests for other reasons.
+func (environProviderCredentials) CheckCredential(spec environs.CloudSpec) error {
+	if err := validateCloudSpec(spec); err != nil {
+		return errors.Trace(err)
+	}
+	creds, err := newCredentials(*spec.Credential)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	conn, err := newConnection(google.ConnectionConfig{
+		Region:    spec.Region,
+		ProjectID: creds.ProjectID,
+	}, creds)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = conn.VerifyCredentials()
+	if err == nil {
+		return nil
+	}
+	if google.IsPermissionDenied(err) {
+		account := creds.ClientEmail
+		if creds.TargetServiceAccount != "" {
+			account = creds.TargetServiceAccount
+		}
+		return errors.NewUnauthorized(err, fmt.Sprintf(
+			"credential for %q does not have permission to use project %q", account, creds.ProjectID))
+	}
+	if message, ok := google.ForbiddenMessage(err); ok {
+		// The credential was accepted, but the project cannot be used
+		// for some other reason, such as the Compute Engine API not
+		// being enabled or a quota being exceeded.
+		return errors.Wrap(err, errors.Errorf("cannot use project %q: %s", creds.ProjectID, message))
+	}
+	if google.HasDenialStatusCode(err) {
+		return errors.NewUnauthorized(err, fmt.Sprintf(
+			"credential for %q was rejected, check that the key is valid and has not been revoked", creds.ClientEmail))
+	}
+	return errors.Annotate(err, "checking credential")
+}
+
 // FinalizeCredential is part of the environs.ProviderCredentials interface.
 func (environProviderCredentials) FinalizeCredential(_ environs.FinalizeCredentialContext, args environs.FinalizeCredentialParams) (*cloud.Credential, error) {
+	// The "file" attribute of "jsonfile" and
+	// "service-account-impersonation" credentials may still hold the
+	// path of the file here, in which case its contents are validated
+	// when the file is parsed.
+	attrs := args.Credential.Attributes()
+	switch args.Credential.AuthType() {
+	case cloud.OAuth2AuthType:
+		if err := validatePrivateKey(attrs[credAttrPrivateKey]); err != nil {
+			return nil, errors.Trace(err)
+		}
+	case cloud.JSONFileAuthType:
+		if contents := attrs[credAttrFile]; cloud.IsInlineJSON(contents) {
+			if err := validateJSONKey(contents, true); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if _, err := parseJSONAuthFile(strings.NewReader(contents)); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+	case cloud.ServiceAccountImpersonationAuthType:
+		if contents := attrs[credAttrFile]; cloud.IsInlineJSON(contents) {
+			if _, err := impersonationCredentialAttributes(attrs); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+	}
 	return &args.Credential, nil
 }
@@ -4,11 +4,18 @@
 package gce_test
 
 import (
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
 
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
@@ -17,7 +24,9 @@ import (
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/environs"
 	envtesting "github.com/juju/juju/environs/testing"
+	"github.com/juju/juju/provider/gce"
 	"github.com/juju/juju/provider/gce/google"
+	coretesting "github.com/juju/juju/testing"
 )
 
 type credentialsSuite struct {
@@ -33,17 +42,22 @@ func (s *credentialsSuite) SetUpTest(c *gc.C) {
 	var err error
 	s.provider, err = environs.Provider("gce")
 	c.Assert(err, jc.ErrorIsNil)
+
+	// Ensure detection never reads the real gcloud configuration or
+	// metadata server.
+	s.PatchEnvironment("CLOUDSDK_CONFIG", c.MkDir())
+	s.PatchValue(gce.MetadataEndpoint, "")
 }
 
 func (s *credentialsSuite) TestCredentialSchemas(c *gc.C) {
-	envtesting.AssertProviderAuthTypes(c, s.provider, "oauth2", "jsonfile")
+	envtesting.AssertProviderAuthTypes(c, s.provider, "oauth2", "jsonfile", "service-account-impersonation")
 }
 
 var sampleCredentialAttributes = map[string]string{
 	"GCE_CLIENT_ID":    "123",
 	"GCE_CLIENT_EMAIL": "test@example.com",
 	"GCE_PROJECT_ID":   "fourfivesix",
-	"GCE_PRIVATE_KEY":  "sewen",
+	"GCE_PRIVATE_KEY":  coretesting.CAKey,
 }
 
 func (s *credentialsSuite) TestOAuth2CredentialsValid(c *gc.C) {
@@ -55,6 +69,121 @@ func (s *credentialsSuite) TestOAuth2CredentialsValid(c *gc.C) {
 	})
 }
 
+func (s *credentialsSuite) TestFinalizeCredentialOAuth2(c *gc.C) {
+	in := cloud.NewCredential(cloud.OAuth2AuthType, map[string]string{
+		"client-id":    "123",
+		"client-email": "test@example.com",
+		"project-id":   "fourfivesix",
+		"private-key":  coretesting.CAKey,
+	})
+	out, err := s.provider.FinalizeCredential(cmdtesting.Context(c), environs.FinalizeCredentialParams{
+		Credential: in,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.DeepEquals, &in)
+}
+
+func (s *credentialsSuite) TestFinalizeCredentialOAuth2InvalidPrivateKey(c *gc.C) {
+	in := cloud.NewCredential(cloud.OAuth2AuthType, map[string]string{
+		"client-id":    "123",
+		"client-email": "test@example.com",
+		"project-id":   "fourfivesix",
+		"private-key":  "sewen",
+	})
+	_, err := s.provider.FinalizeCredential(cmdtesting.Context(c), environs.FinalizeCredentialParams{
+		Credential: in,
+	})
+	c.Assert(err, gc.ErrorMatches, `"private-key" is not a PEM encoded RSA private key`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *credentialsSuite) TestFinalizeCredentialJSONFileUnvalidated(c *gc.C) {
+	in := cloud.NewCredential(cloud.JSONFileAuthType, map[string]string{
+		"file": "/path/to/creds.json",
+	})
+	out, err := s.provider.FinalizeCredential(cmdtesting.Context(c), environs.FinalizeCredentialParams{
+		Credential: in,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.DeepEquals, &in)
+}
+
+// inlineJSONKey returns a service account key holding the sample
+// credential attributes, less any of the given fields.
+func inlineJSONKey(c *gc.C, without ...string) string {
+	key := map[string]string{
+		"type":         "service_account",
+		"client_id":    sampleCredentialAttributes["GCE_CLIENT_ID"],
+		"client_email": sampleCredentialAttributes["GCE_CLIENT_EMAIL"],
+		"project_id":   sampleCredentialAttributes["GCE_PROJECT_ID"],
+		"private_key":  sampleCredentialAttributes["GCE_PRIVATE_KEY"],
+	}
+	for _, field := range without {
+		delete(key, field)
+	}
+	data, err := json.Marshal(key)
+	c.Assert(err, jc.ErrorIsNil)
+	return string(data)
+}
+
+func (s *credentialsSuite) TestFinalizeCredentialJSONFileInline(c *gc.C) {
+	in := cloud.NewCredential(cloud.JSONFileAuthType, map[string]string{
+		"file": inlineJSONKey(c),
+	})
+	// The schema leaves inline JSON in place rather than reading a file.
+	finalized, err := cloud.FinalizeCredential(in, s.provider.CredentialSchemas(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(finalized.Attributes(), jc.DeepEquals, in.Attributes())
+
+	out, err := s.provider.FinalizeCredential(cmdtesting.Context(c), environs.FinalizeCredentialParams{
+		Credential: *finalized,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.DeepEquals, finalized)
+}
+
+func (s *credentialsSuite) TestFinalizeCredentialJSONFileInlineMissingFields(c *gc.C) {
+	in := cloud.NewCredential(cloud.JSONFileAuthType, map[string]string{
+		"file": inlineJSONKey(c, "client_email", "project_id"),
+	})
+	_, err := s.provider.FinalizeCredential(cmdtesting.Context(c), environs.FinalizeCredentialParams{
+		Credential: in,
+	})
+	c.Assert(err, gc.ErrorMatches, `service account key missing "client_email", "project_id"`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *credentialsSuite) TestFinalizeCredentialJSONFileInlineInvalid(c *gc.C) {
+	in := cloud.NewCredential(cloud.JSONFileAuthType, map[string]string{
+		"file": `{"client_email": `,
+	})
+	_, err := s.provider.FinalizeCredential(cmdtesting.Context(c), environs.FinalizeCredentialParams{
+		Credential: in,
+	})
+	c.Assert(err, gc.ErrorMatches, `service account key is not valid JSON: .*`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *credentialsSuite) TestNewCredentialsJSONFileInline(c *gc.C) {
+	cred := cloud.NewCredential(cloud.JSONFileAuthType, map[string]string{
+		"file": inlineJSONKey(c),
+	})
+	creds, err := gce.NewCredentials(cred)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(creds.ClientID, gc.Equals, "123")
+	c.Assert(creds.ClientEmail, gc.Equals, "test@example.com")
+	c.Assert(creds.ProjectID, gc.Equals, "fourfivesix")
+	c.Assert(string(creds.PrivateKey), gc.Equals, coretesting.CAKey)
+}
+
+func (s *credentialsSuite) TestNewCredentialsJSONFileMissingProject(c *gc.C) {
+	cred := cloud.NewCredential(cloud.JSONFileAuthType, map[string]string{
+		"file": inlineJSONKey(c, "project_id"),
+	})
+	_, err := gce.NewCredentials(cred)
+	c.Assert(err, gc.ErrorMatches, `service account key missing "project_id"`)
+}
+
 func (s *credentialsSuite) TestOAuth2HiddenAttributes(c *gc.C) {
 	envtesting.AssertProviderCredentialsAttributesHidden(c, s.provider, "oauth2", "private-key")
 }
@@ -72,6 +201,100 @@ func (s *credentialsSuite) TestJSONFileCredentialsValid(c *gc.C) {
 	})
 }
 
+func (s *credentialsSuite) TestServiceAccountImpersonationCredentialsValid(c *gc.C) {
+	dir := c.MkDir()
+	filename := filepath.Join(dir, "somefile")
+	err := ioutil.WriteFile(filename, []byte("contents"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	envtesting.AssertProviderCredentialsValid(c, s.provider, "service-account-impersonation", map[string]string{
+		"file":                   filename,
+		"target-service-account": "target@example.com",
+		"delegates":              "delegate@example.com",
+		"project-id":             "sevenate",
+	})
+}
+
+func (s *credentialsSuite) TestNewCredentialsServiceAccountImpersonation(c *gc.C) {
+	source, err := google.NewCredentials(sampleCredentialAttributes)
+	c.Assert(err, jc.ErrorIsNil)
+	cred := cloud.NewCredential(cloud.ServiceAccountImpersonationAuthType, map[string]string{
+		"file":                   string(source.JSONKey),
+		"target-service-account": "target@example.com",
+		"delegates":              "one@example.com, two@example.com",
+	})
+	creds, err := gce.NewCredentials(cred)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(creds.ClientEmail, gc.Equals, "test@example.com")
+	c.Check(creds.ProjectID, gc.Equals, "fourfivesix")
+	c.Check(creds.TargetServiceAccount, gc.Equals, "target@example.com")
+	c.Check(creds.Delegates, jc.DeepEquals, []string{"one@example.com", "two@example.com"})
+}
+
+func (s *credentialsSuite) TestNewCredentialsServiceAccountImpersonationProjectID(c *gc.C) {
+	source, err := google.NewCredentials(sampleCredentialAttributes)
+	c.Assert(err, jc.ErrorIsNil)
+	cred := cloud.NewCredential(cloud.ServiceAccountImpersonationAuthType, map[string]string{
+		"file":                   string(source.JSONKey),
+		"target-service-account": "target@example.com",
+		"project-id":             "sevenate",
+	})
+	creds, err := gce.NewCredentials(cred)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(creds.ProjectID, gc.Equals, "sevenate")
+	c.Check(creds.Delegates, gc.HasLen, 0)
+}
+
+func (s *credentialsSuite) TestNewCredentialsServiceAccountImpersonationMissingFields(c *gc.C) {
+	cred := cloud.NewCredential(cloud.ServiceAccountImpersonationAuthType, map[string]string{
+		"file":                   inlineJSONKey(c, "private_key", "project_id"),
+		"target-service-account": "target@example.com",
+	})
+	_, err := gce.NewCredentials(cred)
+	c.Assert(err, gc.ErrorMatches, `service account key missing "private_key", "project_id"`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *credentialsSuite) TestNewCredentialsServiceAccountImpersonationKeyWithoutProject(c *gc.C) {
+	// The project of the key is not needed if one is specified.
+	cred := cloud.NewCredential(cloud.ServiceAccountImpersonationAuthType, map[string]string{
+		"file":                   inlineJSONKey(c, "project_id"),
+		"target-service-account": "target@example.com",
+		"project-id":             "sevenate",
+	})
+	creds, err := gce.NewCredentials(cred)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(creds.ProjectID, gc.Equals, "sevenate")
+}
+
+func (s *credentialsSuite) TestFinalizeCredentialServiceAccountImpersonationInline(c *gc.C) {
+	in := cloud.NewCredential(cloud.ServiceAccountImpersonationAuthType, map[string]string{
+		"file":                   inlineJSONKey(c),
+		"target-service-account": "target@example.com",
+	})
+	// The schema leaves inline JSON in place rather than reading a file.
+	finalized, err := cloud.FinalizeCredential(in, s.provider.CredentialSchemas(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(finalized.Attributes(), jc.DeepEquals, in.Attributes())
+
+	out, err := s.provider.FinalizeCredential(cmdtesting.Context(c), environs.FinalizeCredentialParams{
+		Credential: *finalized,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.DeepEquals, finalized)
+}
+
+func (s *credentialsSuite) TestFinalizeCredentialServiceAccountImpersonationInlineMissingFields(c *gc.C) {
+	in := cloud.NewCredential(cloud.ServiceAccountImpersonationAuthType, map[string]string{
+		"file":                   inlineJSONKey(c, "client_email"),
+		"target-service-account": "target@example.com",
+	})
+	_, err := s.provider.FinalizeCredential(cmdtesting.Context(c), environs.FinalizeCredentialParams{
+		Credential: in,
+	})
+	c.Assert(err, gc.ErrorMatches, `service account key missing "client_email"`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
 func createCredsFile(c *gc.C, path string) string {
 	if path == "" {
 		dir := c.MkDir()
@@ -84,6 +307,34 @@ func createCredsFile(c *gc.C, path string) string {
 	return path
 }
 
+func (s *credentialsSuite) TestDetectCredentialsNotFound(c *gc.C) {
+	home := utils.Home()
+	err := utils.SetHome(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) {
+		err := utils.SetHome(home)
+		c.Assert(err, jc.ErrorIsNil)
+	})
+	s.PatchEnvironment("APPDATA", c.MkDir())
+	s.PatchEnvironment("GOOGLE_APPLICATION_CREDENTIALS", "")
+	_, err = s.provider.DetectCredentials()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *credentialsSuite) TestDetectCredentialsEnvVarMissingFile(c *gc.C) {
+	home := utils.Home()
+	err := utils.SetHome(c.MkDir())
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) {
+		err := utils.SetHome(home)
+		c.Assert(err, jc.ErrorIsNil)
+	})
+	s.PatchEnvironment("APPDATA", c.MkDir())
+	s.PatchEnvironment("GOOGLE_APPLICATION_CREDENTIALS", filepath.Join(c.MkDir(), "missing.json"))
+	_, err = s.provider.DetectCredentials()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *credentialsSuite) TestDetectCredentialsFromEnvVar(c *gc.C) {
 	jsonpath := createCredsFile(c, "")
 	s.PatchEnvironment("USER", "fred")
@@ -97,6 +348,149 @@ func (s *credentialsSuite) TestDetectCredentialsFromEnvVar(c *gc.C) {
 	c.Assert(credentials.AuthCredentials["fred"], jc.DeepEquals, expected)
 }
 
+func (s *credentialsSuite) TestDetectCredentialsImpersonation(c *gc.C) {
+	jsonpath := createCredsFile(c, "")
+	s.PatchEnvironment("USER", "fred")
+	s.PatchEnvironment("GOOGLE_APPLICATION_CREDENTIALS", jsonpath)
+	s.PatchEnvironment("CLOUDSDK_AUTH_IMPERSONATE_SERVICE_ACCOUNT", "delegate@example.com,target@example.com")
+	credentials, err := s.provider.DetectCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	expected := cloud.NewCredential(cloud.ServiceAccountImpersonationAuthType, map[string]string{
+		"file":                   jsonpath,
+		"target-service-account": "target@example.com",
+		"delegates":              "delegate@example.com",
+	})
+	expected.Label = `google credential "target@example.com"`
+	c.Assert(credentials.AuthCredentials["fred"], jc.DeepEquals, expected)
+}
+
+func (s *credentialsSuite) TestDetectCredentialsGcloudConfig(c *gc.C) {
+	dir := c.MkDir()
+	err := ioutil.WriteFile(filepath.Join(dir, "active_config"), []byte("work\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	err = os.MkdirAll(filepath.Join(dir, "configurations"), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(dir, "configurations", "config_work"), []byte(`
+[core]
+project = my-project
+
+[compute]
+zone = europe-west1-b
+`), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	jsonpath := createCredsFile(c, "")
+	s.PatchEnvironment("USER", "fred")
+	s.PatchEnvironment("GOOGLE_APPLICATION_CREDENTIALS", jsonpath)
+	s.PatchEnvironment("CLOUDSDK_CONFIG", dir)
+	credentials, err := s.provider.DetectCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(credentials.DefaultRegion, gc.Equals, "europe-west1")
+	c.Assert(credentials.AuthCredentials["fred"].Label, gc.Equals,
+		`google credential "test@example.com" (project "my-project", zone "europe-west1-b")`)
+
+	// The environment takes precedence over the gcloud configuration.
+	s.PatchEnvironment("CLOUDSDK_COMPUTE_REGION", "region")
+	s.PatchEnvironment("CLOUDSDK_CORE_PROJECT", "env-project")
+	credentials, err = s.provider.DetectCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(credentials.DefaultRegion, gc.Equals, "region")
+	c.Assert(credentials.AuthCredentials["fred"].Label, gc.Equals,
+		`google credential "test@example.com" (project "env-project", zone "europe-west1-b")`)
+}
+
+func (s *credentialsSuite) TestDetectCredentialsMetadataServer(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Metadata-Flavor") != "Google" {
+			http.Error(w, "missing Metadata-Flavor header", http.StatusForbidden)
+			return
+		}
+		switch req.URL.Path {
+		case "/computeMetadata/v1/project/project-id":
+			fmt.Fprint(w, "metadata-project")
+		case "/computeMetadata/v1/instance/zone":
+			fmt.Fprint(w, "projects/123456/zones/us-central1-f")
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer server.Close()
+	s.PatchValue(gce.MetadataEndpoint, server.URL+"/computeMetadata/v1/")
+
+	jsonpath := createCredsFile(c, "")
+	s.PatchEnvironment("USER", "fred")
+	s.PatchEnvironment("GOOGLE_APPLICATION_CREDENTIALS", jsonpath)
+	credentials, err := s.provider.DetectCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(credentials.DefaultRegion, gc.Equals, "us-central1")
+	c.Assert(credentials.AuthCredentials["fred"].Label, gc.Equals,
+		`google credential "test@example.com" (project "metadata-project", zone "us-central1-f")`)
+}
+
+func (s *credentialsSuite) TestDetectCredentialsMetadataHostEnvironment(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/computeMetadata/v1/project/project-id":
+			fmt.Fprint(w, "metadata-project")
+		case "/computeMetadata/v1/instance/zone":
+			fmt.Fprint(w, "projects/123456/zones/us-central1-f")
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	c.Assert(err, jc.ErrorIsNil)
+	// The default endpoint is unreachable, so the metadata can only
+	// come from the host given in the environment.
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+	s.PatchValue(gce.MetadataEndpoint, unreachable.URL+"/computeMetadata/v1/")
+	s.PatchEnvironment("GCE_METADATA_HOST", serverURL.Host)
+
+	jsonpath := createCredsFile(c, "")
+	s.PatchEnvironment("USER", "fred")
+	s.PatchEnvironment("GOOGLE_APPLICATION_CREDENTIALS", jsonpath)
+	credentials, err := s.provider.DetectCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(credentials.DefaultRegion, gc.Equals, "us-central1")
+	c.Assert(credentials.AuthCredentials["fred"].Label, gc.Equals,
+		`google credential "test@example.com" (project "metadata-project", zone "us-central1-f")`)
+}
+
+func (s *credentialsSuite) TestDetectCredentialsMetadataServerUnreachable(c *gc.C) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	s.PatchValue(gce.MetadataEndpoint, server.URL+"/computeMetadata/v1/")
+
+	jsonpath := createCredsFile(c, "")
+	s.PatchEnvironment("USER", "fred")
+	s.PatchEnvironment("GOOGLE_APPLICATION_CREDENTIALS", jsonpath)
+	credentials, err := s.provider.DetectCredentials()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(credentials.DefaultRegion, gc.Equals, "")
+	expected := cloud.NewCredential(cloud.JSONFileAuthType, map[string]string{"file": jsonpath})
+	expected.Label = `google credential "test@example.com"`
+	c.Assert(credentials.AuthCredentials["fred"], jc.DeepEquals, expected)
+}
+
+func (s *credentialsSuite) TestDetectCredentialsInvalidPrivateKey(c *gc.C) {
+	attrs := make(map[string]string)
+	for k, v := range sampleCredentialAttributes {
+		attrs[k] = v
+	}
+	attrs["GCE_PRIVATE_KEY"] = "sewen"
+	creds, err := google.NewCredentials(attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	jsonpath := filepath.Join(c.MkDir(), "creds.json")
+	err = ioutil.WriteFile(jsonpath, creds.JSONKey, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.PatchEnvironment("GOOGLE_APPLICATION_CREDENTIALS", jsonpath)
+	_, err = s.provider.DetectCredentials()
+	c.Assert(err, gc.ErrorMatches, `invalid json credential file .*: "private-key" is not a PEM encoded RSA private key`)
+}
+
 func (s *credentialsSuite) assertDetectCredentialsKnownLocation(c *gc.C, jsonpath string) {
 	s.PatchEnvironment("USER", "fred")
 	s.PatchEnvironment("CLOUDSDK_COMPUTE_REGION", "region")
@@ -103,21 +103,9 @@ func newEnviron(cloud environs.CloudSpec, cfg *config.Config) (*environ, error)
 		return nil, errors.Annotate(err, "invalid config")
 	}
 
-	credAttrs := cloud.Credential.Attributes()
-	if cloud.Credential.AuthType() == jujucloud.JSONFileAuthType {
-		contents := credAttrs[credAttrFile]
-		credential, err := parseJSONAuthFile(strings.NewReader(contents))
-		if err != nil {
-			return nil, errors.Trace(err)
-		}
-		credAttrs = credential.Attributes()
-	}
-
-	credential := &google.Credentials{
-		ClientID:    credAttrs[credAttrClientID],
-		ProjectID:   credAttrs[credAttrProjectID],
-		ClientEmail: credAttrs[credAttrClientEmail],
-		PrivateKey:  []byte(credAttrs[credAttrPrivateKey]),
+	credential, err := newCredentials(*cloud.Credential)
+	if err != nil {
+		return nil, errors.Trace(err)
 	}
 	connectionConfig := google.ConnectionConfig{
 		Region:    cloud.Region,
@@ -144,6 +132,39 @@ func newEnviron(cloud environs.CloudSpec, cfg *config.Config) (*environ, error)
 	}, nil
 }
 
+// newCredentials returns the google.Credentials used to connect to GCE
+// with the given cloud credential.
+func newCredentials(cred jujucloud.Credential) (*google.Credentials, error) {
+	credAttrs := cred.Attributes()
+	switch cred.AuthType() {
+	case jujucloud.JSONFileAuthType:
+		contents := credAttrs[credAttrFile]
+		if err := validateJSONKey(contents, true); err != nil {
+			return nil, errors.Trace(err)
+		}
+		credential, err := parseJSONAuthFile(strings.NewReader(contents))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		credAttrs = credential.Attributes()
+	case jujucloud.ServiceAccountImpersonationAuthType:
+		var err error
+		credAttrs, err = impersonationCredentialAttributes(credAttrs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	return &google.Credentials{
+		ClientID:             credAttrs[credAttrClientID],
+		ProjectID:            credAttrs[credAttrProjectID],
+		ClientEmail:          credAttrs[credAttrClientEmail],
+		PrivateKey:           []byte(credAttrs[credAttrPrivateKey]),
+		TargetServiceAccount: credAttrs[credAttrTargetServiceAccount],
+		Delegates:            splitDelegates(credAttrs[credAttrDelegates]),
+	}, nil
+}
+
 // Name returns the name of the environment.
 func (env *environ) Name() string {
 	return env.name
@@ -21,6 +21,8 @@ var (
 	UbuntuImageBasePath                               = ubuntuImageBasePath
 	UbuntuDailyImageBasePath                          = ubuntuDailyImageBasePath
 	WindowsImageBasePath                              = windowsImageBasePath
+	NewCredentials                                    = newCredentials
+	MetadataEndpoint                                  = &metadataEndpoint
 )
 
 func ExposeInstBase(inst instances.Instance) *google.Instance {
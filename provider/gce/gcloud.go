@@ -0,0 +1,187 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/ini.v1"
+)
+
+var (
+	// metadataEndpoint is the base URL of the metadata server available
+	// to GCE instances. The link-local address is used rather than
+	// metadata.google.internal so that no DNS lookup is needed when not
+	// running on GCE. Detection from the metadata server is disabled if
+	// it is empty.
+	metadataEndpoint = "http://169.254.169.254/computeMetadata/v1/"
+
+	// metadataTimeout bounds each request to the metadata server, so
+	// that detection does not stall when not running on GCE. The
+	// metadata server is local to the instance, so it answers quickly
+	// when present.
+	metadataTimeout = 300 * time.Millisecond
+)
+
+// metadataHostEnvVar is the environment variable used by the Google
+// client libraries to override the host of the metadata server.
+const metadataHostEnvVar = "GCE_METADATA_HOST"
+
+// gcloudDefaults holds the project, region and zone that the gcloud
+// command-line tool, or the metadata server, is configured to use.
+type gcloudDefaults struct {
+	project string
+	region  string
+	zone    string
+}
+
+// detectGcloudDefaults returns the default project, region and zone.
+// Each is taken from the first of the CLOUDSDK_* environment variables,
+// the active gcloud configuration and the metadata server to provide
+// it. Any of them may be empty if not found.
+func detectGcloudDefaults() gcloudDefaults {
+	d := gcloudDefaults{
+		project: os.Getenv("CLOUDSDK_CORE_PROJECT"),
+		region:  os.Getenv("CLOUDSDK_COMPUTE_REGION"),
+		zone:    os.Getenv("CLOUDSDK_COMPUTE_ZONE"),
+	}
+	d.merge(readGcloudConfig(gcloudConfigDir()))
+	if d.project == "" || d.zone == "" {
+		d.merge(readMetadataDefaults(metadataServerEndpoint()))
+	}
+	if d.region == "" && d.zone != "" {
+		d.region = zoneRegion(d.zone)
+	}
+	return d
+}
+
+// metadataServerEndpoint returns the base URL of the metadata server,
+// using the host given by GCE_METADATA_HOST if it is set.
+func metadataServerEndpoint() string {
+	if metadataEndpoint == "" {
+		return ""
+	}
+	if host := os.Getenv(metadataHostEnvVar); host != "" {
+		return "http://" + host + "/computeMetadata/v1/"
+	}
+	return metadataEndpoint
+}
+
+// merge fills in any unset values of d from other.
+func (d *gcloudDefaults) merge(other gcloudDefaults) {
+	if d.project == "" {
+		d.project = other.project
+	}
+	if d.region == "" {
+		d.region = other.region
+	}
+	if d.zone == "" {
+		d.zone = other.zone
+	}
+}
+
+// gcloudConfigDir returns the directory holding the gcloud
+// configurations.
+func gcloudConfigDir() string {
+	if dir := os.Getenv("CLOUDSDK_CONFIG"); dir != "" {
+		return dir
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "gcloud")
+	}
+	return filepath.Join(utils.Home(), ".config", "gcloud")
+}
+
+// readGcloudConfig returns the defaults set in the active gcloud
+// configuration held in the given directory.
+func readGcloudConfig(dir string) gcloudDefaults {
+	active := os.Getenv("CLOUDSDK_ACTIVE_CONFIG_NAME")
+	if active == "" {
+		data, err := ioutil.ReadFile(filepath.Join(dir, "active_config"))
+		if err == nil {
+			active = strings.TrimSpace(string(data))
+		}
+	}
+	if active == "" {
+		active = "default"
+	}
+	configFile := filepath.Join(dir, "configurations", "config_"+active)
+	config, err := ini.LooseLoad(configFile)
+	if err != nil {
+		logger.Debugf("cannot load gcloud configuration %q: %v", configFile, err)
+		return gcloudDefaults{}
+	}
+	return gcloudDefaults{
+		project: config.Section("core").Key("project").String(),
+		region:  config.Section("compute").Key("region").String(),
+		zone:    config.Section("compute").Key("zone").String(),
+	}
+}
+
+// readMetadataDefaults returns the project and zone of the GCE instance
+// whose metadata server is at the given endpoint. Empty values are
+// returned if the metadata server cannot be reached.
+func readMetadataDefaults(endpoint string) gcloudDefaults {
+	if endpoint == "" {
+		return gcloudDefaults{}
+	}
+	client := &http.Client{Timeout: metadataTimeout}
+	project, err := readMetadataValue(client, endpoint, "project/project-id")
+	if err != nil {
+		logger.Debugf("cannot read project from metadata server: %v", err)
+		return gcloudDefaults{}
+	}
+	// The zone is given as "projects/<number>/zones/<zone>".
+	zone, err := readMetadataValue(client, endpoint, "instance/zone")
+	if err != nil {
+		logger.Debugf("cannot read zone from metadata server: %v", err)
+	}
+	if zone != "" {
+		zone = path.Base(zone)
+	}
+	return gcloudDefaults{
+		project: project,
+		zone:    zone,
+	}
+}
+
+// readMetadataValue returns the value of the given metadata server key.
+func readMetadataValue(client *http.Client, endpoint, key string) (string, error) {
+	req, err := http.NewRequest("GET", strings.TrimSuffix(endpoint, "/")+"/"+key, nil)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("getting %q: %s", key, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// zoneRegion returns the region containing the given zone,
+// e.g. "us-central1" for "us-central1-a".
+func zoneRegion(zone string) string {
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		return zone[:i]
+	}
+	return ""
+}
@@ -15,11 +15,19 @@ var (
 		"https://www.googleapis.com/auth/compute",
 		"https://www.googleapis.com/auth/devstorage.full_control",
 	}
+
+	// impersonationScopes are the scopes needed by the source
+	// credentials to request tokens for an impersonated account.
+	impersonationScopes = []string{
+		"https://www.googleapis.com/auth/cloud-platform",
+	}
 )
 
 // newConnection opens a new low-level connection to the GCE API using
 // the Auth's data and returns it. This includes building the
-// OAuth-wrapping network transport.
+// OAuth-wrapping network transport. If the credentials name a target
+// service account, the transport uses short-lived tokens for that
+// account obtained with the source credentials.
 func newConnection(creds *Credentials) (*compute.Service, error) {
 	jsonKey := creds.JSONKey
 	if jsonKey == nil {
@@ -29,11 +37,27 @@ func newConnection(creds *Credentials) (*compute.Service, error) {
 		}
 		jsonKey = built
 	}
-	cfg, err := goauth2.JWTConfigFromJSON(jsonKey, driverScopes...)
+	if creds.TargetServiceAccount == "" {
+		cfg, err := goauth2.JWTConfigFromJSON(jsonKey, driverScopes...)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		service, err := compute.New(cfg.Client(oauth2.NoContext))
+		return service, errors.Trace(err)
+	}
+
+	cfg, err := goauth2.JWTConfigFromJSON(jsonKey, impersonationScopes...)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	client := cfg.Client(oauth2.NoContext)
+	client := oauth2.NewClient(oauth2.NoContext, oauth2.ReuseTokenSource(nil, &impersonatedTokenSource{
+		client:    cfg.Client(oauth2.NoContext),
+		endpoint:  iamCredentialsEndpoint,
+		target:    creds.TargetServiceAccount,
+		delegates: creds.Delegates,
+		scopes:    driverScopes,
+		lifetime:  impersonatedTokenLifetime,
+	}))
 	service, err := compute.New(client)
 	return service, errors.Trace(err)
 }
@@ -18,3 +18,9 @@ func (s *authSuite) TestNewConnection(c *gc.C) {
 	_, err := newConnection(s.Credentials)
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (s *authSuite) TestNewConnectionImpersonated(c *gc.C) {
+	s.Credentials.TargetServiceAccount = "target@project.iam.gserviceaccount.com"
+	_, err := newConnection(s.Credentials)
+	c.Assert(err, jc.ErrorIsNil)
+}
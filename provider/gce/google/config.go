@@ -24,6 +24,8 @@ const (
 	OSEnvRegion        = "GCE_REGION"
 	OSEnvProjectID     = "GCE_PROJECT_ID"
 	OSEnvImageEndpoint = "GCE_IMAGE_URL"
+
+	OSEnvTargetServiceAccount = "GCE_TARGET_SERVICE_ACCOUNT"
 )
 
 const (
@@ -51,6 +53,17 @@ type Credentials struct {
 	// associatd with the GCE account. It is used to generate a new
 	// OAuth token to use in the OAuth-wrapping network transport.
 	PrivateKey []byte
+
+	// TargetServiceAccount, if set, is the email address of a service
+	// account to impersonate. The other fields then describe the source
+	// account, which is only used to request short-lived access tokens
+	// for the target.
+	TargetServiceAccount string
+
+	// Delegates is the optional chain of service accounts through which
+	// the impersonation of TargetServiceAccount is delegated. Each
+	// account in the chain must be allowed to create tokens for the next.
+	Delegates []string
 }
 
 // NewCredentials returns a new Credentials based on the provided
@@ -163,7 +176,7 @@ func (gc Credentials) Values() map[string]string {
 //
 // To be considered valid, each of the credentials must be set to some
 // non-empty value. Furthermore, ClientEmail must be a proper email
-// address.
+// address, as must TargetServiceAccount if it is set.
 func (gc Credentials) Validate() error {
 	if gc.ClientID == "" {
 		return NewMissingConfigValue(OSEnvClientID, "ClientID")
@@ -177,6 +190,11 @@ func (gc Credentials) Validate() error {
 	if len(gc.PrivateKey) == 0 {
 		return NewMissingConfigValue(OSEnvPrivateKey, "PrivateKey")
 	}
+	if gc.TargetServiceAccount != "" {
+		if _, err := mail.ParseAddress(gc.TargetServiceAccount); err != nil {
+			return NewInvalidConfigValueError(OSEnvTargetServiceAccount, gc.TargetServiceAccount, err)
+		}
+	}
 	return nil
 }
 
@@ -153,3 +153,16 @@ func (*credentialsSuite) TestValidateMissingKey(c *gc.C) {
 	c.Assert(err, jc.Satisfies, google.IsInvalidConfigValueError)
 	c.Check(err.(*google.InvalidConfigValueError).Key, gc.Equals, "GCE_PRIVATE_KEY")
 }
+
+func (*credentialsSuite) TestValidateBadTargetServiceAccount(c *gc.C) {
+	creds := &google.Credentials{
+		ClientID:             "spam",
+		ClientEmail:          "user@mail.com",
+		PrivateKey:           []byte("non-empty"),
+		TargetServiceAccount: "bad_email",
+	}
+	err := creds.Validate()
+
+	c.Assert(err, jc.Satisfies, google.IsInvalidConfigValueError)
+	c.Check(err.(*google.InvalidConfigValueError).Key, gc.Equals, "GCE_TARGET_SERVICE_ACCOUNT")
+}
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"google.golang.org/api/googleapi"
 
@@ -111,6 +112,76 @@ func HasDenialStatusCode(err error) bool {
 	return false
 }
 
+// permissionDeniedReasons holds the reasons the GCE API gives in a 403
+// response when the account lacks the permissions for a request. Other
+// 403 reasons, such as accessNotConfigured, rateLimitExceeded,
+// quotaExceeded or billing problems, do not mean missing permissions.
+var permissionDeniedReasons = set.NewStrings("forbidden", "insufficientPermissions")
+
+// forbiddenCause returns the cause of err if it is a 403 response from
+// the GCE API or from impersonating a service account, or nil otherwise.
+func forbiddenCause(err error) error {
+	cause := errors.Cause(err)
+	if uerr, ok := cause.(*url.Error); ok {
+		cause = errors.Cause(uerr.Err)
+	}
+	switch e := cause.(type) {
+	case *googleapi.Error:
+		if e.Code == http.StatusForbidden {
+			return e
+		}
+	case *ImpersonationError:
+		if e.StatusCode == http.StatusForbidden {
+			return e
+		}
+	}
+	return nil
+}
+
+// IsPermissionDenied reports whether the given error was caused by the
+// GCE API refusing a request made with credentials that it accepted,
+// because the account lacks the necessary IAM roles, or by the source
+// account not being allowed to impersonate the target account.
+func IsPermissionDenied(err error) bool {
+	switch e := forbiddenCause(err).(type) {
+	case *googleapi.Error:
+		for _, item := range e.Errors {
+			if permissionDeniedReasons.Contains(item.Reason) {
+				return true
+			}
+		}
+	case *ImpersonationError:
+		return true
+	}
+	return false
+}
+
+// ForbiddenMessage returns the message the server sent with a 403
+// response, and whether err was caused by such a response. The message
+// includes the reasons given by the GCE API, if any.
+func ForbiddenMessage(err error) (string, bool) {
+	switch e := forbiddenCause(err).(type) {
+	case *googleapi.Error:
+		message := e.Message
+		var reasons []string
+		for _, item := range e.Errors {
+			if message == "" {
+				message = item.Message
+			}
+			if item.Reason != "" {
+				reasons = append(reasons, item.Reason)
+			}
+		}
+		if len(reasons) > 0 {
+			message = fmt.Sprintf("%s (%s)", message, strings.Join(reasons, ", "))
+		}
+		return message, true
+	case *ImpersonationError:
+		return e.Response, true
+	}
+	return "", false
+}
+
 // AuthorisationFailureStatusCodes contains http status code and
 // description that signify authorisation difficulties.
 //
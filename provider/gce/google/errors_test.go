@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"google.golang.org/api/googleapi"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/environs/context"
@@ -32,6 +33,73 @@ func (s *ErrorSuite) SetUpTest(c *gc.C) {
 	s.googleError = &url.Error{"Get", "http://notforreal.com/", s.internalError}
 }
 
+func (s *ErrorSuite) TestIsPermissionDenied(c *gc.C) {
+	for _, reason := range []string{"forbidden", "insufficientPermissions"} {
+		c.Logf("reason %q", reason)
+		forbidden := forbiddenError(reason, "Required 'compute.projects.get' permission")
+		c.Check(google.IsPermissionDenied(forbidden), jc.IsTrue)
+		c.Check(google.IsPermissionDenied(errors.Annotate(forbidden, "getting project")), jc.IsTrue)
+	}
+	c.Assert(google.IsPermissionDenied(&googleapi.Error{Code: http.StatusUnauthorized}), jc.IsFalse)
+	c.Assert(google.IsPermissionDenied(s.googleError), jc.IsFalse)
+	c.Assert(google.IsPermissionDenied(nil), jc.IsFalse)
+}
+
+func (s *ErrorSuite) TestIsPermissionDeniedOtherForbiddenReasons(c *gc.C) {
+	for _, reason := range []string{
+		"accessNotConfigured",
+		"rateLimitExceeded",
+		"quotaExceeded",
+		"billingNotEnabled",
+	} {
+		c.Logf("reason %q", reason)
+		c.Check(google.IsPermissionDenied(forbiddenError(reason, "refused")), jc.IsFalse)
+	}
+	c.Assert(google.IsPermissionDenied(&googleapi.Error{Code: http.StatusForbidden}), jc.IsFalse)
+}
+
+func (s *ErrorSuite) TestForbiddenMessage(c *gc.C) {
+	message, ok := google.ForbiddenMessage(errors.Annotate(
+		forbiddenError("accessNotConfigured", "Compute Engine API has not been used in project 42"), "getting project"))
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(message, gc.Equals, "Compute Engine API has not been used in project 42 (accessNotConfigured)")
+
+	message, ok = google.ForbiddenMessage(&googleapi.Error{Code: http.StatusForbidden, Message: "Forbidden"})
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(message, gc.Equals, "Forbidden")
+
+	_, ok = google.ForbiddenMessage(&googleapi.Error{Code: http.StatusUnauthorized, Message: "Unauthorized"})
+	c.Assert(ok, jc.IsFalse)
+	_, ok = google.ForbiddenMessage(s.googleError)
+	c.Assert(ok, jc.IsFalse)
+}
+
+func forbiddenError(reason, message string) *googleapi.Error {
+	return &googleapi.Error{
+		Code:    http.StatusForbidden,
+		Message: message,
+		Errors: []googleapi.ErrorItem{{
+			Reason:  reason,
+			Message: message,
+		}},
+	}
+}
+
+func (s *ErrorSuite) TestIsPermissionDeniedImpersonation(c *gc.C) {
+	impersonationError := func(code int, status string) error {
+		return &url.Error{Op: "Get", URL: "http://notforreal.com/", Err: &google.ImpersonationError{
+			Target:     "target@project.iam.gserviceaccount.com",
+			StatusCode: code,
+			Status:     status,
+		}}
+	}
+	forbidden := impersonationError(http.StatusForbidden, "403 Forbidden")
+	c.Assert(google.IsPermissionDenied(forbidden), jc.IsTrue)
+	c.Assert(google.IsPermissionDenied(errors.Annotate(forbidden, "getting project")), jc.IsTrue)
+	c.Assert(google.IsPermissionDenied(impersonationError(http.StatusUnauthorized, "401 Unauthorized")), jc.IsFalse)
+	c.Assert(google.HasDenialStatusCode(impersonationError(http.StatusUnauthorized, "401 Unauthorized")), jc.IsTrue)
+}
+
 func (s *ErrorSuite) TestNilContext(c *gc.C) {
 	err := google.HandleCredentialError(s.googleError, nil)
 	c.Assert(err, gc.DeepEquals, s.googleError)
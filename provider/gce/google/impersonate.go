@@ -0,0 +1,134 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package google
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/juju/errors"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// iamCredentialsEndpoint is the base URL of the IAM Service
+	// Account Credentials API.
+	iamCredentialsEndpoint = "https://iamcredentials.googleapis.com/v1/"
+
+	// impersonatedTokenLifetime is how long each impersonated access
+	// token is requested to be valid for. One hour is the maximum
+	// allowed by default.
+	impersonatedTokenLifetime = time.Hour
+)
+
+// impersonatedTokenSource is an oauth2.TokenSource that exchanges the
+// source credentials of the wrapped client for short-lived access
+// tokens belonging to a different service account.
+type impersonatedTokenSource struct {
+	// client is an HTTP client authenticated as the source account.
+	client *http.Client
+
+	// endpoint is the base URL of the IAM Service Account Credentials API.
+	endpoint string
+
+	target    string
+	delegates []string
+	scopes    []string
+	lifetime  time.Duration
+}
+
+type generateAccessTokenRequest struct {
+	Delegates []string `json:"delegates,omitempty"`
+	Scope     []string `json:"scope"`
+	Lifetime  string   `json:"lifetime"`
+}
+
+type generateAccessTokenResponse struct {
+	AccessToken string    `json:"accessToken"`
+	ExpireTime  time.Time `json:"expireTime"`
+}
+
+// Token implements oauth2.TokenSource.
+func (ts *impersonatedTokenSource) Token() (*oauth2.Token, error) {
+	req := generateAccessTokenRequest{
+		Scope:    ts.scopes,
+		Lifetime: fmt.Sprintf("%ds", int64(ts.lifetime/time.Second)),
+	}
+	for _, delegate := range ts.delegates {
+		req.Delegates = append(req.Delegates, serviceAccountResource(delegate))
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	tokenURL := ts.endpoint + serviceAccountResource(ts.target) + ":generateAccessToken"
+	resp, err := ts.client.Post(tokenURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Annotatef(err, "impersonating service account %q", ts.target)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Annotatef(err, "impersonating service account %q", ts.target)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &ImpersonationError{
+			Target:     ts.target,
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Response:   string(respBody),
+		}
+	}
+
+	var result generateAccessTokenResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, errors.Annotatef(err, "impersonating service account %q", ts.target)
+	}
+	if result.AccessToken == "" {
+		return nil, errors.Errorf("impersonating service account %q: no access token returned", ts.target)
+	}
+	return &oauth2.Token{
+		AccessToken: result.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      result.ExpireTime,
+	}, nil
+}
+
+// ImpersonationError is returned when the IAM Service Account
+// Credentials API refuses to issue an access token for the target
+// service account. When the token is requested by an HTTP transport,
+// it is usually found wrapped in a *url.Error.
+type ImpersonationError struct {
+	// Target is the service account being impersonated.
+	Target string
+
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Status is the HTTP status of the response, e.g. "403 Forbidden".
+	Status string
+
+	// Response is the body of the response.
+	Response string
+}
+
+// Error implements error. The status is given in the same form as the
+// oauth2 package uses, so that HasDenialStatusCode recognises
+// authorisation failures.
+func (e *ImpersonationError) Error() string {
+	return fmt.Sprintf("impersonating service account %q: %v\nResponse: %s", e.Target, e.Status, e.Response)
+}
+
+// serviceAccountResource returns the IAM resource name for the
+// service account with the given email address.
+func serviceAccountResource(email string) string {
+	return "projects/-/serviceAccounts/" + url.PathEscape(email)
+}
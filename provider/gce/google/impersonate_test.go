@@ -0,0 +1,77 @@
+// Copyright 2019 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package google
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type impersonateSuite struct {
+	BaseSuite
+}
+
+var _ = gc.Suite(&impersonateSuite{})
+
+func (s *impersonateSuite) TestToken(c *gc.C) {
+	expiry := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	var received generateAccessTokenRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.Method, gc.Equals, "POST")
+		c.Check(r.URL.EscapedPath(), gc.Equals, "/projects/-/serviceAccounts/target@project.iam.gserviceaccount.com:generateAccessToken")
+		err := json.NewDecoder(r.Body).Decode(&received)
+		c.Check(err, jc.ErrorIsNil)
+		json.NewEncoder(w).Encode(generateAccessTokenResponse{
+			AccessToken: "short-lived",
+			ExpireTime:  expiry,
+		})
+	}))
+	defer server.Close()
+
+	ts := &impersonatedTokenSource{
+		client:    http.DefaultClient,
+		endpoint:  server.URL + "/",
+		target:    "target@project.iam.gserviceaccount.com",
+		delegates: []string{"delegate@project.iam.gserviceaccount.com"},
+		scopes:    driverScopes,
+		lifetime:  impersonatedTokenLifetime,
+	}
+	token, err := ts.Token()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(token.AccessToken, gc.Equals, "short-lived")
+	c.Check(token.TokenType, gc.Equals, "Bearer")
+	c.Check(token.Expiry.Equal(expiry), jc.IsTrue)
+
+	c.Check(received, jc.DeepEquals, generateAccessTokenRequest{
+		Delegates: []string{"projects/-/serviceAccounts/delegate@project.iam.gserviceaccount.com"},
+		Scope:     driverScopes,
+		Lifetime:  "3600s",
+	})
+}
+
+func (s *impersonateSuite) TestTokenDenied(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "permission denied", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	ts := &impersonatedTokenSource{
+		client:   http.DefaultClient,
+		endpoint: server.URL + "/",
+		target:   "target@project.iam.gserviceaccount.com",
+		scopes:   driverScopes,
+		lifetime: impersonatedTokenLifetime,
+	}
+	_, err := ts.Token()
+	c.Assert(err, gc.ErrorMatches, `(?s)impersonating service account "target@project.iam.gserviceaccount.com": 403 Forbidden.*permission denied.*`)
+	c.Assert(err, gc.FitsTypeOf, &ImpersonationError{})
+	c.Assert(err.(*ImpersonationError).StatusCode, gc.Equals, http.StatusForbidden)
+	c.Assert(IsPermissionDenied(&url.Error{Op: "Get", URL: server.URL, Err: err}), jc.IsTrue)
+}
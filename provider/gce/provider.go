@@ -70,7 +70,7 @@ func validateCloudSpec(spec environs.CloudSpec) error {
 		return errors.NotValidf("missing credential")
 	}
 	switch authType := spec.Credential.AuthType(); authType {
-	case cloud.OAuth2AuthType, cloud.JSONFileAuthType:
+	case cloud.OAuth2AuthType, cloud.JSONFileAuthType, cloud.ServiceAccountImpersonationAuthType:
 	default:
 		return errors.NotSupportedf("%q auth-type", authType)
 	}
@@ -4,12 +4,18 @@
 package gce_test
 
 import (
+	"net/http"
+	"net/url"
+
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"google.golang.org/api/googleapi"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/cloud"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/provider/gce"
+	"github.com/juju/juju/provider/gce/google"
 )
 
 type providerSuite struct {
@@ -100,3 +106,78 @@ func (s *providerSuite) TestUpgradeConfig(c *gc.C) {
 	c.Assert(ok, jc.IsTrue)
 	c.Assert(source, gc.Equals, "gce")
 }
+
+func (s *providerSuite) TestCheckCredential(c *gc.C) {
+	c.Assert(s.provider, gc.Implements, new(environs.CredentialChecker))
+	checker := s.provider.(environs.CredentialChecker)
+
+	err := checker.CheckCredential(s.spec)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 1)
+}
+
+func (s *providerSuite) TestCheckCredentialMissingCredential(c *gc.C) {
+	s.spec.Credential = nil
+	err := s.provider.(environs.CredentialChecker).CheckCredential(s.spec)
+	c.Assert(err, gc.ErrorMatches, "missing credential not valid")
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 0)
+}
+
+func (s *providerSuite) TestCheckCredentialPermissionDenied(c *gc.C) {
+	s.FakeConn.Err = &googleapi.Error{
+		Code:    http.StatusForbidden,
+		Message: "Required 'compute.projects.get' permission",
+		Errors:  []googleapi.ErrorItem{{Reason: "forbidden"}},
+	}
+	err := s.provider.(environs.CredentialChecker).CheckCredential(s.spec)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+	c.Assert(err, gc.ErrorMatches, `credential for "`+gce.ClientEmail+`" does not have permission to use project "`+gce.ProjectID+`": .*`)
+}
+
+func (s *providerSuite) TestCheckCredentialProjectRefused(c *gc.C) {
+	s.FakeConn.Err = &googleapi.Error{
+		Code:    http.StatusForbidden,
+		Message: "Compute Engine API has not been used in project " + gce.ProjectID,
+		Errors:  []googleapi.ErrorItem{{Reason: "accessNotConfigured"}},
+	}
+	err := s.provider.(environs.CredentialChecker).CheckCredential(s.spec)
+	c.Assert(err, gc.Not(jc.Satisfies), errors.IsUnauthorized)
+	c.Assert(err, gc.ErrorMatches, `cannot use project "`+gce.ProjectID+`": Compute Engine API has not been used in project `+gce.ProjectID+` \(accessNotConfigured\)`)
+}
+
+func (s *providerSuite) TestCheckCredentialImpersonationDenied(c *gc.C) {
+	s.FakeConn.Err = &url.Error{
+		Op:  "Get",
+		URL: "https://www.googleapis.com/compute/v1/projects/" + gce.ProjectID,
+		Err: &google.ImpersonationError{
+			Target:     "target@project.iam.gserviceaccount.com",
+			StatusCode: http.StatusForbidden,
+			Status:     "403 Forbidden",
+			Response:   "Permission 'iam.serviceAccounts.getAccessToken' denied",
+		},
+	}
+	err := s.provider.(environs.CredentialChecker).CheckCredential(s.spec)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+	c.Assert(err, gc.ErrorMatches, `credential for "`+gce.ClientEmail+`" does not have permission to use project "`+gce.ProjectID+`": .*`)
+}
+
+func (s *providerSuite) TestCheckCredentialInvalidSpec(c *gc.C) {
+	s.spec.Type = ""
+	err := s.provider.(environs.CredentialChecker).CheckCredential(s.spec)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(s.FakeConn.Calls, gc.HasLen, 0)
+}
+
+func (s *providerSuite) TestCheckCredentialRejected(c *gc.C) {
+	s.FakeConn.Err = gce.InvalidCredentialError
+	err := s.provider.(environs.CredentialChecker).CheckCredential(s.spec)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+	c.Assert(err, gc.ErrorMatches, `credential for "`+gce.ClientEmail+`" was rejected, check that the key is valid and has not been revoked: .*`)
+}
+
+func (s *providerSuite) TestCheckCredentialOtherError(c *gc.C) {
+	s.FakeConn.Err = errors.New("connection refused")
+	err := s.provider.(environs.CredentialChecker).CheckCredential(s.spec)
+	c.Assert(err, gc.Not(jc.Satisfies), errors.IsUnauthorized)
+	c.Assert(err, gc.ErrorMatches, "checking credential: connection refused")
+}
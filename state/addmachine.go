@@ -343,6 +343,9 @@ func (st *State) addMachineInsideMachineOps(template MachineTemplate, parentId s
 	if containerType == "" {
 		return nil, nil, errors.New("no container type specified")
 	}
+	if !instance.IsSupportedContainerType(containerType) {
+		return nil, nil, errors.NotSupportedf("%s containers", containerType)
+	}
 
 	// If a parent machine is specified, make sure it exists
 	// and can support the requested container type.
@@ -353,6 +356,12 @@ func (st *State) addMachineInsideMachineOps(template MachineTemplate, parentId s
 	if !parent.supportsContainerType(containerType) {
 		return nil, nil, errors.Errorf("machine %s cannot host %s containers", parentId, containerType)
 	}
+	if hostType := parent.ContainerType(); hostType != "" && hostType != instance.NONE {
+		// Only some container types can be nested inside others.
+		if err := instance.ValidateNesting(hostType, containerType); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+	}
 
 	// Ensure that the machine is not locked for series-upgrade.
 	locked, err := parent.IsLockedForSeriesUpgrade()
@@ -400,6 +409,9 @@ func (st *State) addMachineInsideNewMachineOps(template, parentTemplate MachineT
 	if template.InstanceId != "" || parentTemplate.InstanceId != "" {
 		return nil, nil, errors.New("cannot specify instance id for a new container")
 	}
+	if containerType != "" && !instance.IsSupportedContainerType(containerType) {
+		return nil, nil, errors.NotSupportedf("%s containers", containerType)
+	}
 	seq, err := sequence(st, "machine")
 	if err != nil {
 		return nil, nil, err
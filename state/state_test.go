@@ -1249,6 +1249,58 @@ func (s *StateSuite) TestAddContainerToMachineLockedForSeriesUpgrade(c *gc.C) {
 	s.assertMachineContainers(c, host, nil)
 }
 
+func (s *StateSuite) TestAddContainerUnsupportedNesting(c *gc.C) {
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.AddMachineInsideMachine(template, "0", instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddMachineInsideMachine(template, container.Id(), instance.KVM)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: kvm container inside lxd container not supported")
+	s.assertMachineContainers(c, container, nil)
+}
+
+func (s *StateSuite) TestAddContainerUnsupportedType(c *gc.C) {
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	host, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddMachineInsideMachine(template, host.Id(), instance.PODMAN)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: podman containers not supported")
+	s.assertMachineContainers(c, host, nil)
+
+	_, err = s.State.AddMachineInsideNewMachine(template, template, instance.PODMAN)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "cannot add a new machine: podman containers not supported")
+}
+
+func (s *StateSuite) TestAddContainerSupportedNesting(c *gc.C) {
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	guest, err := s.State.AddMachineInsideMachine(template, "0", instance.KVM)
+	c.Assert(err, jc.ErrorIsNil)
+
+	m, err := s.State.AddMachineInsideMachine(template, guest.Id(), instance.KVM)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, "0/kvm/0/kvm/0")
+	m, err = s.State.AddMachineInsideMachine(template, guest.Id(), instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.Id(), gc.Equals, "0/kvm/0/lxd/0")
+}
+
 func (s *StateSuite) TestInvalidAddMachineParams(c *gc.C) {
 	instIdTemplate := state.MachineTemplate{
 		Series:     "quantal",
@@ -131,6 +131,32 @@ func (s *UnitAssignmentSuite) TestAssignUnitWithPlacementDirective(c *gc.C) {
 	c.Assert(machine.Placement(), gc.Equals, "zone=test")
 }
 
+func (s *UnitAssignmentSuite) TestAssignUnitWithPlacementUnsupportedNesting(c *gc.C) {
+	_, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, "0", instance.LXD)
+	c.Assert(err, jc.ErrorIsNil)
+
+	charm := s.AddTestingCharm(c, "dummy")
+	app, err := s.State.AddApplication(state.AddApplicationArgs{
+		Name:  "dummy",
+		Charm: charm,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	placement := instance.Placement{Scope: "kvm", Directive: container.Id()}
+	err = s.State.AssignUnitWithPlacement(unit, &placement)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, ".*kvm container inside lxd container not supported")
+	_, err = unit.AssignedMachineId()
+	c.Assert(err, jc.Satisfies, errors.IsNotAssigned)
+}
+
 func (s *UnitAssignmentSuite) TestAssignUnitCleanMachineUpgradeSeriesLockError(c *gc.C) {
 	s.addLockedMachine(c, true)
 
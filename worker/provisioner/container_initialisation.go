@@ -108,8 +108,16 @@ func (cs *ContainerSetup) Handle(abort <-chan struct{}, containerIds []string) (
 	cs.logger.Infof("initial container setup with ids: %v", containerIds)
 	for _, id := range containerIds {
 		containerType := state.ContainerTypeFromId(id)
+		done, ok := cs.setupDone[containerType]
+		if !ok || !broker.SupportsContainerType(containerType) {
+			// There is no broker for this container type yet, so there is
+			// nothing to start. Returning an error here would only cause
+			// the worker to be restarted repeatedly.
+			cs.logger.Warningf("ignoring container %q: %s containers cannot be provisioned", id, containerType)
+			continue
+		}
 		// If this container type has been dealt with, do nothing.
-		if atomic.LoadInt32(cs.setupDone[containerType]) != 0 {
+		if atomic.LoadInt32(done) != 0 {
 			continue
 		}
 		if err := cs.initialiseAndStartProvisioner(abort, containerType); err != nil {
@@ -149,11 +157,6 @@ func (cs *ContainerSetup) initialiseAndStartProvisioner(
 
 	cs.logger.Debugf("setup and start provisioner for %s containers", containerType)
 
-	// Do an early check.
-	if containerType != instance.LXD && containerType != instance.KVM {
-		return fmt.Errorf("unknown container type: %v", containerType)
-	}
-
 	// Get the container manager config before other initialisation,
 	// so we know if there are issues with host machine config.
 	managerConfig, err := containerManagerConfig(containerType, cs.provisioner)
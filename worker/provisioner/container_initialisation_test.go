@@ -128,6 +128,19 @@ func (s *containerSetupSuite) TestContainerManagerConfigError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, ".*generating container manager config: boom")
 }
 
+func (s *containerSetupSuite) TestContainerTypeWithoutProvisionerIgnored(c *gc.C) {
+	defer s.patch(c).Finish()
+
+	s.notify(nil)
+	handler, runner := s.setUpContainerWorker(c)
+	s.cleanKill(c, runner)
+
+	abort := make(chan struct{})
+	close(abort)
+	err := handler.Handle(abort, []string{"0/podman/0"})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *containerSetupSuite) setUpContainerWorker(c *gc.C) (watcher.StringsHandler, *worker.Runner) {
 	runner := worker.NewRunner(worker.RunnerParams{
 		IsFatal:       func(_ error) bool { return true },